@@ -7,5 +7,11 @@ type Cell struct {
 	Foreground concolor.Color
 	Background concolor.Color
 
+	// ForegroundIndex and BackgroundIndex reference colors of the palette of the
+	// console. If they are set and the palette contains the color it is drawn
+	// instead of Foreground or Background.
+	ForegroundIndex concolor.PaletteIndex
+	BackgroundIndex concolor.PaletteIndex
+
 	Char int
 }
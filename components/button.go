@@ -5,7 +5,6 @@ import (
 	"github.com/BigJk/ramen/console"
 	"github.com/BigJk/ramen/t"
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 )
 
 // ClickedCallback will be called if a click on the component happened.
@@ -55,16 +54,23 @@ func (b *Button) FocusOnClick() bool {
 // Update updates the button
 func (b *Button) Update(con *console.Console, timeElapsed float64) bool {
 	b.state = CalculateComponentState(con, b.X, b.Y, b.Width, b.Height)
+	return true
+}
 
-	if b.state == ComponentHovered && inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) {
+// HandleEvent triggers the callback if the button is clicked or enter is pressed
+// while it is focused. Clicks on the button won't reach the components and consoles
+// under it.
+func (b *Button) HandleEvent(con *console.Console, event *console.Event) {
+	switch {
+	case event.Type == console.EventMousePress && event.Button == ebiten.MouseButtonLeft:
+		event.Handled = true
+	case event.Type == console.EventMouseRelease && event.Button == ebiten.MouseButtonLeft:
+		event.Handled = true
 		b.clickedCallback()
-	}
-
-	if b.IsFocused() && inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+	case event.Type == console.EventKeyDown && event.Key == ebiten.KeyEnter:
+		event.Handled = true
 		b.clickedCallback()
 	}
-
-	return true
 }
 
 // Draw draws the button
@@ -0,0 +1,137 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/BigJk/ramen/console"
+	"github.com/BigJk/ramen/font"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// step changes the input and runs the updates of the console that are needed for it.
+type step func(con *console.Console, in *console.MemoryInput)
+
+func click(x, y int) step {
+	return func(con *console.Console, in *console.MemoryInput) {
+		in.SetCursor(x*con.Font.TileWidth, y*con.Font.TileHeight)
+		in.PressButton(ebiten.MouseButtonLeft)
+		_ = con.Update()
+		in.ReleaseButton(ebiten.MouseButtonLeft)
+		_ = con.Update()
+	}
+}
+
+func typeText(text string) step {
+	return func(con *console.Console, in *console.MemoryInput) {
+		in.TypeText(text)
+		_ = con.Update()
+	}
+}
+
+func press(key ebiten.Key) step {
+	return func(con *console.Console, in *console.MemoryInput) {
+		in.PressKey(key)
+		_ = con.Update()
+		in.ReleaseKey(key)
+		_ = con.Update()
+	}
+}
+
+func newTestConsole(t *testing.T, component console.Component) (*console.Console, *console.MemoryInput) {
+	con, err := console.New(20, 5, font.DefaultFont, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	in := console.NewMemoryInput()
+	if err := con.SetInput(in); err != nil {
+		t.Fatal(err)
+	}
+
+	con.AddComponent(component)
+	return con, in
+}
+
+func TestButton(t *testing.T) {
+	tests := []struct {
+		name   string
+		steps  []step
+		clicks int
+	}{
+		{"click inside", []step{click(3, 1)}, 1},
+		{"click outside", []step{click(15, 1)}, 0},
+		{"enter without focus", []step{press(ebiten.KeyEnter)}, 0},
+		{"double click", []step{click(2, 1), click(4, 2)}, 2},
+		{"tab and enter", []step{press(ebiten.KeyTab), press(ebiten.KeyEnter)}, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clicks := 0
+			con, in := newTestConsole(t, NewButton(1, 1, 10, 2, "OK", func() { clicks++ }))
+
+			for _, s := range test.steps {
+				s(con, in)
+			}
+
+			assert.Equal(t, test.clicks, clicks)
+		})
+	}
+}
+
+func TestTextBox(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []step
+		text  string
+		enter string
+	}{
+		{"type without focus", []step{typeText("abc")}, "", ""},
+		{"type and enter", []step{click(3, 1), typeText("abc"), press(ebiten.KeyEnter)}, "abc", "abc"},
+		{"backspace", []step{click(3, 1), typeText("abcd"), press(ebiten.KeyBackspace)}, "abc", ""},
+		{"lose focus", []step{click(3, 1), typeText("ab"), click(15, 3), typeText("c")}, "ab", ""},
+		{"focus with tab", []step{press(ebiten.KeyTab), typeText("abc")}, "abc", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tb := NewTextbox(1, 1, 10, 1)
+			con, in := newTestConsole(t, tb)
+
+			var entered string
+			tb.SetEnterCallback(func(text string) {
+				entered = text
+			})
+
+			for _, s := range test.steps {
+				s(con, in)
+			}
+
+			assert.Equal(t, test.text, tb.GetText())
+			assert.Equal(t, test.enter, entered)
+		})
+	}
+}
+
+func TestModalCoversTextBox(t *testing.T) {
+	tb := NewTextbox(1, 1, 10, 1)
+	con, in := newTestConsole(t, tb)
+
+	modal, err := con.CreateSubConsole(0, 0, 20, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clicks := 0
+	modal.AddComponent(NewButton(0, 0, 20, 5, "OK", func() { clicks++ }))
+
+	// The click is handled by the button of the modal and must not focus the
+	// textbox below it.
+	click(3, 1)(con, in)
+	typeText("zzz")(con, in)
+
+	assert.Equal(t, 1, clicks)
+	assert.Nil(t, con.Focused())
+	assert.Equal(t, "", tb.GetText())
+}
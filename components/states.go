@@ -20,7 +20,7 @@ const (
 // CalculateComponentState is generic helper function to calculate the state of a given area in the console.
 func CalculateComponentState(con *console.Console, x, y, w, h int) ComponentState {
 	if con.MouseInArea(x, y, w, h) {
-		if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+		if con.Input().IsMouseButtonPressed(ebiten.MouseButtonLeft) {
 			return ComponentClicked
 		}
 		return ComponentHovered
@@ -2,12 +2,12 @@ package components
 
 import (
 	"sync"
+	"unicode/utf8"
 
 	"github.com/BigJk/ramen/concolor"
 	"github.com/BigJk/ramen/console"
 	"github.com/BigJk/ramen/t"
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 )
 
 // TextChangeCallback will be called if a text of the component has been changed.
@@ -60,36 +60,48 @@ func (tb *TextBox) FocusOnClick() bool {
 // Update updates the textbox.
 func (tb *TextBox) Update(con *console.Console, timeElapsed float64) bool {
 	tb.state = CalculateComponentState(con, tb.X, tb.Y, tb.Width, tb.Height)
+	return true
+}
 
-	if !tb.IsFocused() {
-		return true
-	}
-
-	textChanged := false
-
-	tb.mtx.Lock()
-	if len(ebiten.InputChars()) > 0 {
-		textChanged = true
-	}
-	tb.text += string(ebiten.InputChars())
+// HandleEvent handles the typed text, backspace and enter while the textbox is focused.
+func (tb *TextBox) HandleEvent(con *console.Console, event *console.Event) {
+	switch {
+	case event.Type == console.EventMousePress && event.Button == ebiten.MouseButtonLeft:
+		event.Handled = true
+	case event.Type == console.EventTextInput:
+		event.Handled = true
+
+		tb.mtx.Lock()
+		tb.text += string(event.Char)
+		text := tb.text
+		tb.mtx.Unlock()
+
+		if tb.textChangeCallback != nil {
+			tb.textChangeCallback(text)
+		}
+	case (event.Type == console.EventKeyDown || event.Type == console.EventKeyRepeat) && event.Key == ebiten.KeyBackspace:
+		event.Handled = true
 
-	if tb.repeatingKeyPressed(ebiten.KeyBackspace) {
-		if len(tb.text) >= 1 {
-			tb.text = tb.text[:len(tb.text)-1]
-			textChanged = true
+		tb.mtx.Lock()
+		if len(tb.text) == 0 {
+			tb.mtx.Unlock()
+			return
 		}
-	}
+		_, size := utf8.DecodeLastRuneInString(tb.text)
+		tb.text = tb.text[:len(tb.text)-size]
+		text := tb.text
+		tb.mtx.Unlock()
 
-	if textChanged && tb.textChangeCallback != nil {
-		tb.textChangeCallback(tb.text)
-	}
-	tb.mtx.Unlock()
+		if tb.textChangeCallback != nil {
+			tb.textChangeCallback(text)
+		}
+	case event.Type == console.EventKeyDown && event.Key == ebiten.KeyEnter:
+		event.Handled = true
 
-	if tb.enterCallback != nil && inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
-		tb.enterCallback(tb.text)
+		if tb.enterCallback != nil {
+			tb.enterCallback(tb.GetText())
+		}
 	}
-
-	return true
 }
 
 // Draw draws the textbox.
@@ -195,18 +207,3 @@ func (tb *TextBox) SetForeground(active, inactive *concolor.Color) {
 		tb.foregroundInactive = *inactive
 	}
 }
-
-func (tb *TextBox) repeatingKeyPressed(key ebiten.Key) bool {
-	const (
-		delay    = 30
-		interval = 3
-	)
-	d := inpututil.KeyPressDuration(key)
-	if d == 1 {
-		return true
-	}
-	if d >= delay && (d-delay)%interval == 0 {
-		return true
-	}
-	return false
-}
@@ -0,0 +1,58 @@
+package concolor
+
+import "math"
+
+// BlendMode specifies how a color is composited onto another color.
+type BlendMode int
+
+const (
+	// BlendAlphaOver paints the color over the other color.
+	BlendAlphaOver = BlendMode(0)
+	// BlendMultiply multiplies the colors, which darkens the other color.
+	BlendMultiply = BlendMode(1)
+	// BlendAdd adds the colors, which lightens the other color.
+	BlendAdd = BlendMode(2)
+	// BlendScreen inverts, multiplies and inverts the colors again, which lightens
+	// the other color without over-saturating it as much as BlendAdd.
+	BlendScreen = BlendMode(3)
+)
+
+// Blend composites the src color onto the color with the given mode. The alpha of
+// src is used as the opacity of the blending, so a src with an alpha of 0 leaves the
+// color unchanged.
+func (c Color) Blend(src Color, mode BlendMode) Color {
+	if src.A == 0 {
+		return c
+	}
+
+	var blend func(dst, src float64) float64
+	switch mode {
+	case BlendMultiply:
+		blend = func(dst, src float64) float64 { return dst * src }
+	case BlendAdd:
+		blend = func(dst, src float64) float64 { return math.Min(dst+src, 1) }
+	case BlendScreen:
+		blend = func(dst, src float64) float64 { return 1 - (1-dst)*(1-src) }
+	default:
+		blend = func(dst, src float64) float64 { return src }
+	}
+
+	_, _, _, sa := src.Floats()
+	_, _, _, da := c.Floats()
+	a := sa + da*(1-sa)
+
+	// Where the color is transparent the src color is used as is, otherwise the
+	// blended color is composited over the color.
+	mix := func(dst, src byte) byte {
+		d, s := float64(dst)/0xff, float64(src)/0xff
+		s = (1-da)*s + da*blend(d, s)
+		return byte(math.Round((s*sa + d*da*(1-sa)) / a * 0xff))
+	}
+
+	return Color{
+		R: mix(c.R, src.R),
+		G: mix(c.G, src.G),
+		B: mix(c.B, src.B),
+		A: byte(math.Round(a * 0xff)),
+	}
+}
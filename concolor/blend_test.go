@@ -0,0 +1,20 @@
+package concolor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlend(t *testing.T) {
+	base := RGB(200, 100, 0)
+
+	assert.Equal(t, base, base.Blend(RGBA(255, 255, 255, 0), BlendAlphaOver))
+	assert.Equal(t, Black, base.Blend(Black, BlendAlphaOver))
+	assert.Equal(t, RGB(100, 50, 0), base.Blend(RGBA(0, 0, 0, 128), BlendAlphaOver))
+	assert.Equal(t, RGB(100, 50, 0), base.Blend(RGB(128, 128, 128), BlendMultiply))
+	assert.Equal(t, RGB(255, 150, 50), base.Blend(RGB(100, 50, 50), BlendAdd))
+	assert.Equal(t, RGB(255, 255, 255), base.Blend(White, BlendScreen))
+	assert.Equal(t, RGB(10, 10, 10), RGBA(0, 0, 0, 0).Blend(RGB(10, 10, 10), BlendAlphaOver))
+	assert.Equal(t, RGBA(200, 100, 50, 128), RGBA(0, 0, 0, 0).Blend(RGBA(200, 100, 50, 128), BlendMultiply))
+}
@@ -1,10 +1,7 @@
 // Package concolor provides console color creation functions.
 package concolor
 
-import (
-	"errors"
-	"fmt"
-)
+import "strings"
 
 // Color represents a RGBA color in the console
 type Color struct {
@@ -24,23 +21,14 @@ func RGBA(r, g, b, a byte) Color {
 	return Color{r, g, b, a}
 }
 
-// Hex creates a new color from a hex string
+// Hex creates a new color from a hex string in the #rgb, #rgba, #rrggbb or #rrggbbaa
+// notation. The leading # is optional.
 func Hex(hex string) (Color, error) {
-	if len(hex) != 6 || len(hex) != 4 {
-		return Color{}, errors.New("wrong hex color length")
+	col, err := parseHex(strings.TrimPrefix(strings.TrimSpace(hex), "#"))
+	if err != nil {
+		return Color{}, &ParseError{Input: hex, Err: err}
 	}
-
-	format := "#%02x%02x%02x"
-	if len(hex) == 4 {
-		format = "#%1x%1x%1x"
-	}
-
-	var r, g, b byte
-	if _, err := fmt.Sscanf(hex, format, &r, &g, &b); err != nil {
-		return Color{}, err
-	}
-
-	return Color{r, g, b, 255}, nil
+	return col, nil
 }
 
 // MustHex creates a new color from a hex string and instead of returning an error if
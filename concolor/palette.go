@@ -0,0 +1,196 @@
+package concolor
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// PaletteIndex references a color in a palette. The zero value doesn't reference
+// any color, so use Index to create a reference.
+type PaletteIndex int
+
+// Index creates a reference to the color at position i in a palette.
+func Index(i int) PaletteIndex {
+	return PaletteIndex(i + 1)
+}
+
+// Index returns the referenced position in the palette and true if a color is
+// referenced at all.
+func (p PaletteIndex) Index() (int, bool) {
+	return int(p) - 1, p > 0
+}
+
+// Palette represents a list of colors that can be referenced by their index.
+type Palette struct {
+	Name   string
+	Colors []Color
+}
+
+// NewPalette creates a new palette with the given colors.
+func NewPalette(name string, colors ...Color) *Palette {
+	return &Palette{Name: name, Colors: colors}
+}
+
+// Get returns the color at position i of the palette. If the palette doesn't
+// contain the position false is returned.
+func (p *Palette) Get(i int) (Color, bool) {
+	if p == nil || i < 0 || i >= len(p.Colors) {
+		return Color{}, false
+	}
+	return p.Colors[i], true
+}
+
+// Resolve returns the color referenced by the index. If the index doesn't reference
+// a color of the palette the fallback color is returned.
+func (p *Palette) Resolve(index PaletteIndex, fallback Color) Color {
+	if i, ok := index.Index(); ok {
+		if col, ok := p.Get(i); ok {
+			return col
+		}
+	}
+	return fallback
+}
+
+// Nearest returns the position of the color in the palette that is perceptually
+// the closest to the given color. If the palette is empty -1 is returned.
+func (p *Palette) Nearest(col Color) int {
+	return col.Nearest(p.Colors)
+}
+
+// LoadPalette loads a palette file. The format is detected by the file extension:
+// '.gpl' for GIMP palettes, '.hex' for Lospec hex palettes and '.txt' for REXPaint palettes.
+func LoadPalette(path string) (*Palette, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var p *Palette
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".gpl":
+		p, err = ReadGPL(file)
+	case ".hex":
+		p, err = ReadHex(file)
+	case ".txt":
+		p, err = ReadREXPaint(file)
+	default:
+		return nil, fmt.Errorf("unknown palette format '%s'", filepath.Ext(path))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(p.Name) == 0 {
+		p.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	return p, nil
+}
+
+// ReadGPL reads a palette in the GIMP palette format.
+func ReadGPL(reader io.Reader) (*Palette, error) {
+	var p Palette
+
+	scanner := bufio.NewScanner(reader)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == 1:
+			if text != "GIMP Palette" {
+				return nil, fmt.Errorf("missing 'GIMP Palette' header")
+			}
+		case len(text) == 0 || text[0] == '#':
+		case strings.HasPrefix(text, "Name:"):
+			p.Name = strings.TrimSpace(strings.TrimPrefix(text, "Name:"))
+		case strings.HasPrefix(text, "Columns:"):
+		default:
+			fields := strings.Fields(text)
+			if len(fields) < 3 {
+				return nil, fmt.Errorf("line %d: expected r g b values", line)
+			}
+
+			var rgb [3]byte
+			for i := range rgb {
+				val, err := strconv.ParseUint(fields[i], 10, 8)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: invalid color value '%s'", line, fields[i])
+				}
+				rgb[i] = byte(val)
+			}
+
+			p.Colors = append(p.Colors, RGB(rgb[0], rgb[1], rgb[2]))
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// ReadHex reads a palette in the Lospec hex format, which contains one hex color per line.
+func ReadHex(reader io.Reader) (*Palette, error) {
+	var p Palette
+
+	scanner := bufio.NewScanner(reader)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimPrefix(strings.TrimSpace(scanner.Text()), "#")
+		if len(text) == 0 {
+			continue
+		}
+
+		val, err := strconv.ParseUint(text, 16, 32)
+		if err != nil || len(text) != 6 {
+			return nil, fmt.Errorf("line %d: invalid hex color '%s'", line, text)
+		}
+
+		p.Colors = append(p.Colors, RGB(byte(val>>16), byte(val>>8), byte(val)))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+var rexPaintColorRegex = regexp.MustCompile(`\(\s*(\d+)\s*,\s*(\d+)\s*,\s*(\d+)\s*\)`)
+
+// ReadREXPaint reads a palette in the REXPaint palette format, which contains
+// the colors as '(r,g,b)' tuples.
+func ReadREXPaint(reader io.Reader) (*Palette, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	var p Palette
+	for _, match := range rexPaintColorRegex.FindAllStringSubmatch(string(data), -1) {
+		var rgb [3]byte
+		for i := range rgb {
+			val, err := strconv.ParseUint(match[i+1], 10, 8)
+			if err != nil {
+				return nil, fmt.Errorf("invalid color value '%s'", match[i+1])
+			}
+			rgb[i] = byte(val)
+		}
+
+		p.Colors = append(p.Colors, RGB(rgb[0], rgb[1], rgb[2]))
+	}
+
+	return &p, nil
+}
@@ -0,0 +1,47 @@
+package concolor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadGPL(t *testing.T) {
+	p, err := ReadGPL(strings.NewReader("GIMP Palette\nName: Test\nColumns: 2\n#\n255   0   0\tRed\n  0 255   0\tGreen\n"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Test", p.Name)
+		assert.Equal(t, []Color{Red, Green}, p.Colors)
+	}
+
+	_, err = ReadGPL(strings.NewReader("255 0 0\n"))
+	assert.Error(t, err)
+}
+
+func TestReadHex(t *testing.T) {
+	p, err := ReadHex(strings.NewReader("ff0000\r\n0000FF\n\n"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []Color{Red, Blue}, p.Colors)
+	}
+
+	_, err = ReadHex(strings.NewReader("ff00\n"))
+	assert.Error(t, err)
+}
+
+func TestReadREXPaint(t *testing.T) {
+	p, err := ReadREXPaint(strings.NewReader("{ (255,  0,  0) } { (  0,  0,255) }\n{ (255,255,255) }"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []Color{Red, Blue, White}, p.Colors)
+	}
+}
+
+func TestPaletteResolve(t *testing.T) {
+	p := NewPalette("test", Red, Green)
+
+	assert.Equal(t, Green, p.Resolve(Index(1), Black))
+	assert.Equal(t, Black, p.Resolve(Index(2), Black))
+	assert.Equal(t, Black, p.Resolve(PaletteIndex(0), Black))
+
+	var nilPalette *Palette
+	assert.Equal(t, Black, nilPalette.Resolve(Index(0), Black))
+}
@@ -0,0 +1,154 @@
+package concolor
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	// ErrInvalidHex is returned if a hex color has a wrong length or contains invalid digits.
+	ErrInvalidHex = errors.New("invalid hex color")
+	// ErrInvalidFunction is returned if a rgb() or rgba() color is malformed.
+	ErrInvalidFunction = errors.New("invalid color function")
+	// ErrUnknownName is returned if a color name is not a known CSS color.
+	ErrUnknownName = errors.New("unknown color name")
+)
+
+// ParseError is returned if a color can't be parsed. Use errors.Is to check
+// for the reason, which is one of ErrInvalidHex, ErrInvalidFunction or ErrUnknownName.
+type ParseError struct {
+	Input string
+	Err   error
+}
+
+// Error returns the error message
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("can't parse color '%s': %v", e.Input, e.Err)
+}
+
+// Unwrap returns the reason of the error
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Parse creates a new color from a text. The following notations are supported:
+//
+//	#rgb, #rgba, #rrggbb, #rrggbbaa
+//	rgb(255, 0, 0), rgba(255, 0, 0, 0.5), rgb(100% 0% 0% / 50%)
+//	red, cornflowerblue, transparent (all CSS named colors)
+//
+// The text is case-insensitive and surrounding whitespace is ignored.
+func Parse(text string) (Color, error) {
+	trimmed := strings.ToLower(strings.TrimSpace(text))
+
+	var col Color
+	var err error
+	switch {
+	case strings.HasPrefix(trimmed, "#"):
+		col, err = parseHex(trimmed[1:])
+	case strings.HasPrefix(trimmed, "rgb(") || strings.HasPrefix(trimmed, "rgba("):
+		col, err = parseFunction(trimmed)
+	default:
+		var ok bool
+		if col, ok = names[trimmed]; !ok {
+			err = ErrUnknownName
+		}
+	}
+
+	if err != nil {
+		return Color{}, &ParseError{Input: text, Err: err}
+	}
+	return col, nil
+}
+
+// MustParse creates a new color from a text like Parse, but instead of returning an
+// error if the text could not be parsed it will return a transparent color
+func MustParse(text string) Color {
+	col, _ := Parse(text)
+	return col
+}
+
+// Name returns the CSS named color with the given name.
+func Name(name string) (Color, bool) {
+	col, ok := names[strings.ToLower(name)]
+	return col, ok
+}
+
+// parseHex parses the hex digits of a color in the rgb, rgba, rrggbb or rrggbbaa notation.
+func parseHex(hex string) (Color, error) {
+	switch len(hex) {
+	case 3, 4:
+		expanded := make([]byte, 0, 8)
+		for i := range hex {
+			expanded = append(expanded, hex[i], hex[i])
+		}
+		hex = string(expanded)
+	case 6, 8:
+	default:
+		return Color{}, ErrInvalidHex
+	}
+
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+
+	val, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return Color{}, ErrInvalidHex
+	}
+
+	return Color{byte(val >> 24), byte(val >> 16), byte(val >> 8), byte(val)}, nil
+}
+
+// parseFunction parses a color in the rgb() or rgba() notation. The values can be
+// separated by commas or spaces and the alpha by a slash.
+func parseFunction(text string) (Color, error) {
+	open := strings.IndexByte(text, '(')
+	if !strings.HasSuffix(text, ")") {
+		return Color{}, ErrInvalidFunction
+	}
+
+	args := strings.FieldsFunc(text[open+1:len(text)-1], func(r rune) bool {
+		return r == ',' || r == '/' || unicode.IsSpace(r)
+	})
+	if len(args) != 3 && len(args) != 4 {
+		return Color{}, ErrInvalidFunction
+	}
+
+	col := Color{A: 255}
+	for i, channel := range []*byte{&col.R, &col.G, &col.B, &col.A} {
+		if i >= len(args) {
+			break
+		}
+
+		val, percent, err := parseNumber(args[i])
+		if err != nil {
+			return Color{}, ErrInvalidFunction
+		}
+
+		switch {
+		case percent:
+			val = val / 100 * 0xff
+		case i == 3:
+			val *= 0xff
+		}
+
+		if val < 0 || val > 0xff {
+			return Color{}, ErrInvalidFunction
+		}
+		*channel = byte(math.Round(val))
+	}
+
+	return col, nil
+}
+
+// parseNumber parses a number that is optionally followed by a percent sign.
+func parseNumber(text string) (float64, bool, error) {
+	percent := strings.HasSuffix(text, "%")
+	val, err := strconv.ParseFloat(strings.TrimSuffix(text, "%"), 64)
+	return val, percent, err
+}
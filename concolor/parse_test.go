@@ -0,0 +1,69 @@
+package concolor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	valid := map[string]Color{
+		"#f00":                      Red,
+		"#f008":                     RGBA(255, 0, 0, 136),
+		"#353a41":                   RGB(53, 58, 65),
+		"#FF000080":                 RGBA(255, 0, 0, 128),
+		" rgb(255, 0, 0) ":          Red,
+		"rgba(0, 0, 255, 0.5)":      RGBA(0, 0, 255, 128),
+		"rgb(100% 0% 0% / 50%)":     RGBA(255, 0, 0, 128),
+		"RGB(0,255,0)":              RGB(0, 255, 0),
+		"CornflowerBlue":            RGB(100, 149, 237),
+		"green":                     RGB(0, 128, 0),
+		"transparent":               RGBA(0, 0, 0, 0),
+		"rgba(12.4, 200, 99, 100%)": RGB(12, 200, 99),
+	}
+
+	for text, col := range valid {
+		parsed, err := Parse(text)
+		if assert.NoError(t, err, text) {
+			assert.Equal(t, col, parsed, text)
+		}
+	}
+
+	invalid := map[string]error{
+		"#ff00f":           ErrInvalidHex,
+		"#ggg":             ErrInvalidHex,
+		"rgb(255, 0)":      ErrInvalidFunction,
+		"rgb(256, 0, 0)":   ErrInvalidFunction,
+		"rgb(255, 0, 0":    ErrInvalidFunction,
+		"rgba(0, 0, 0, x)": ErrInvalidFunction,
+		"notacolor":        ErrUnknownName,
+		"":                 ErrUnknownName,
+	}
+
+	for text, reason := range invalid {
+		_, err := Parse(text)
+		assert.True(t, errors.Is(err, reason), text)
+
+		var parseErr *ParseError
+		if assert.True(t, errors.As(err, &parseErr), text) {
+			assert.Equal(t, text, parseErr.Input)
+		}
+	}
+}
+
+func TestHex(t *testing.T) {
+	col, err := Hex("#e1e1e1")
+	assert.NoError(t, err)
+	assert.Equal(t, RGB(225, 225, 225), col)
+
+	col, err = Hex("0f0")
+	assert.NoError(t, err)
+	assert.Equal(t, RGB(0, 255, 0), col)
+
+	_, err = Hex("red")
+	assert.True(t, errors.Is(err, ErrInvalidHex))
+
+	assert.Equal(t, RGB(53, 58, 65), MustHex("#353a41"))
+	assert.Equal(t, Color{}, MustHex("#12345"))
+}
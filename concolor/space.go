@@ -0,0 +1,231 @@
+package concolor
+
+import "math"
+
+// HSV creates a new color from hue (0 - 360), saturation (0 - 1) and value (0 - 1)
+func HSV(h, s, v float64) Color {
+	return HSVA(h, s, v, 255)
+}
+
+// HSVA creates a new color from hue (0 - 360), saturation (0 - 1), value (0 - 1) and alpha
+func HSVA(h, s, v float64, a byte) Color {
+	s, v = clamp01(s), clamp01(v)
+	c := v * s
+	r, g, b := hueToRGB(h, c)
+	m := v - c
+	return Color{toByte(r + m), toByte(g + m), toByte(b + m), a}
+}
+
+// HSL creates a new color from hue (0 - 360), saturation (0 - 1) and lightness (0 - 1)
+func HSL(h, s, l float64) Color {
+	return HSLA(h, s, l, 255)
+}
+
+// HSLA creates a new color from hue (0 - 360), saturation (0 - 1), lightness (0 - 1) and alpha
+func HSLA(h, s, l float64, a byte) Color {
+	s, l = clamp01(s), clamp01(l)
+	c := (1 - math.Abs(2*l-1)) * s
+	r, g, b := hueToRGB(h, c)
+	m := l - c/2
+	return Color{toByte(r + m), toByte(g + m), toByte(b + m), a}
+}
+
+// Lab creates a new color from CIELAB values with the D65 white point. l ranges
+// from 0 to 100, a and b roughly from -128 to 127.
+func Lab(l, a, b float64) Color {
+	fy := (l + 16) / 116
+	fx := fy + a/500
+	fz := fy - b/200
+
+	finv := func(t float64) float64 {
+		if t > 6.0/29 {
+			return t * t * t
+		}
+		return 3 * (6.0 / 29) * (6.0 / 29) * (t - 4.0/29)
+	}
+
+	x, y, z := 0.95047*finv(fx), finv(fy), 1.08883*finv(fz)
+
+	rl := 3.2404542*x - 1.5371385*y - 0.4985314*z
+	gl := -0.9692660*x + 1.8760108*y + 0.0415560*z
+	bl := 0.0556434*x - 0.2040259*y + 1.0572252*z
+
+	return Color{toByte(linearToSRGB(rl)), toByte(linearToSRGB(gl)), toByte(linearToSRGB(bl)), 255}
+}
+
+// HSV returns the hue (0 - 360), saturation (0 - 1) and value (0 - 1) of the color
+func (c Color) HSV() (h, s, v float64) {
+	r, g, b, _ := c.Floats()
+	max, min := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+
+	h = hue(r, g, b, max, min)
+	if max > 0 {
+		s = (max - min) / max
+	}
+	return h, s, max
+}
+
+// HSL returns the hue (0 - 360), saturation (0 - 1) and lightness (0 - 1) of the color
+func (c Color) HSL() (h, s, l float64) {
+	r, g, b, _ := c.Floats()
+	max, min := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+
+	h = hue(r, g, b, max, min)
+	l = (max + min) / 2
+	if max != min {
+		s = (max - min) / (1 - math.Abs(2*l-1))
+	}
+	return h, s, l
+}
+
+// Lab returns the CIELAB values of the color with the D65 white point
+func (c Color) Lab() (l, a, b float64) {
+	r, g, bl, _ := c.Floats()
+	r, g, bl = srgbToLinear(r), srgbToLinear(g), srgbToLinear(bl)
+
+	x := (0.4124564*r + 0.3575761*g + 0.1804375*bl) / 0.95047
+	y := 0.2126729*r + 0.7151522*g + 0.0721750*bl
+	z := (0.0193339*r + 0.1191920*g + 0.9503041*bl) / 1.08883
+
+	f := func(t float64) float64 {
+		if t > math.Pow(6.0/29, 3) {
+			return math.Cbrt(t)
+		}
+		return t/(3*(6.0/29)*(6.0/29)) + 4.0/29
+	}
+
+	fx, fy, fz := f(x), f(y), f(z)
+	return 116*fy - 16, 500 * (fx - fy), 200 * (fy - fz)
+}
+
+// Lerp creates a new color by linearly interpolating from the color to the other
+// color. An amount of 0 returns the color and 1 the other color.
+func (c Color) Lerp(to Color, amount float64) Color {
+	if amount <= 0 {
+		return c
+	} else if amount >= 1 {
+		return to
+	}
+
+	l := func(a, b byte) byte {
+		return toByte((float64(a) + (float64(b)-float64(a))*amount) / 0xff)
+	}
+
+	return Color{l(c.R, to.R), l(c.G, to.G), l(c.B, to.B), l(c.A, to.A)}
+}
+
+// Darken creates a new color with the lightness decreased by amount (0 - 1)
+func (c Color) Darken(amount float64) Color {
+	h, s, l := c.HSL()
+	return HSLA(h, s, l-amount, c.A)
+}
+
+// Lighten creates a new color with the lightness increased by amount (0 - 1)
+func (c Color) Lighten(amount float64) Color {
+	h, s, l := c.HSL()
+	return HSLA(h, s, l+amount, c.A)
+}
+
+// Saturate creates a new color with the saturation increased by amount (0 - 1)
+func (c Color) Saturate(amount float64) Color {
+	h, s, l := c.HSL()
+	return HSLA(h, s+amount, l, c.A)
+}
+
+// Desaturate creates a new color with the saturation decreased by amount (0 - 1)
+func (c Color) Desaturate(amount float64) Color {
+	h, s, l := c.HSL()
+	return HSLA(h, s-amount, l, c.A)
+}
+
+// Distance returns the perceptual distance between the colors, which is the
+// euclidean distance in the CIELAB color space (CIE76). A distance of about 2.3
+// is the smallest difference that is noticeable. Alpha is ignored.
+func (c Color) Distance(other Color) float64 {
+	l1, a1, b1 := c.Lab()
+	l2, a2, b2 := other.Lab()
+	return math.Sqrt((l1-l2)*(l1-l2) + (a1-a2)*(a1-a2) + (b1-b2)*(b1-b2))
+}
+
+// Nearest returns the index of the color in the palette that is perceptually the
+// closest to the color. If the palette is empty -1 is returned.
+func (c Color) Nearest(palette []Color) int {
+	nearest := -1
+	best := math.Inf(1)
+	for i := range palette {
+		if d := c.Distance(palette[i]); d < best {
+			nearest = i
+			best = d
+		}
+	}
+	return nearest
+}
+
+func hue(r, g, b, max, min float64) float64 {
+	d := max - min
+	if d == 0 {
+		return 0
+	}
+
+	var h float64
+	switch max {
+	case r:
+		h = math.Mod((g-b)/d, 6)
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return h
+}
+
+// hueToRGB returns the rgb values of the hue with the chroma c without lightness.
+func hueToRGB(h, c float64) (r, g, b float64) {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	h /= 60
+
+	x := c * (1 - math.Abs(math.Mod(h, 2)-1))
+	switch {
+	case h < 1:
+		return c, x, 0
+	case h < 2:
+		return x, c, 0
+	case h < 3:
+		return 0, c, x
+	case h < 4:
+		return 0, x, c
+	case h < 5:
+		return x, 0, c
+	}
+	return c, 0, x
+}
+
+func srgbToLinear(v float64) float64 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func linearToSRGB(v float64) float64 {
+	if v <= 0.0031308 {
+		return v * 12.92
+	}
+	return 1.055*math.Pow(v, 1/2.4) - 0.055
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+func toByte(v float64) byte {
+	return byte(math.Round(clamp01(v) * 0xff))
+}
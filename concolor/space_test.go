@@ -0,0 +1,66 @@
+package concolor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHSV(t *testing.T) {
+	h, s, v := RGB(255, 128, 0).HSV()
+	assert.InDelta(t, 30.1, h, 0.1)
+	assert.InDelta(t, 1, s, 0.001)
+	assert.InDelta(t, 1, v, 0.001)
+
+	assert.Equal(t, RGB(255, 128, 0), HSV(h, s, v))
+	assert.Equal(t, RGB(0, 0, 255), HSV(240, 1, 1))
+	assert.Equal(t, RGBA(0, 0, 255, 10), HSVA(-120, 1, 1, 10))
+}
+
+func TestHSL(t *testing.T) {
+	h, s, l := RGB(64, 128, 192).HSL()
+	assert.InDelta(t, 210, h, 0.1)
+	assert.InDelta(t, 0.5, s, 0.01)
+	assert.InDelta(t, 0.5, l, 0.01)
+
+	assert.Equal(t, RGB(64, 128, 192), HSL(h, s, l))
+	assert.Equal(t, White, HSL(0, 0, 1))
+}
+
+func TestLab(t *testing.T) {
+	l, a, b := White.Lab()
+	assert.InDelta(t, 100, l, 0.01)
+	assert.InDelta(t, 0, a, 0.01)
+	assert.InDelta(t, 0, b, 0.01)
+
+	l, a, b = Red.Lab()
+	assert.InDelta(t, 53.24, l, 0.01)
+	assert.InDelta(t, 80.09, a, 0.01)
+	assert.InDelta(t, 67.20, b, 0.01)
+
+	for _, col := range []Color{Red, Green, Blue, Black, White, RGB(12, 200, 99)} {
+		assert.Equal(t, col, Lab(col.Lab()))
+	}
+}
+
+func TestLerp(t *testing.T) {
+	assert.Equal(t, RGBA(128, 128, 128, 128), RGBA(0, 0, 0, 0).Lerp(White, 0.5))
+	assert.Equal(t, Black, Black.Lerp(White, -1))
+	assert.Equal(t, White, Black.Lerp(White, 2))
+}
+
+func TestAdjustments(t *testing.T) {
+	assert.Equal(t, RGB(128, 0, 0), Red.Darken(0.25))
+	assert.Equal(t, RGB(255, 128, 128), Red.Lighten(0.25))
+	assert.Equal(t, RGB(128, 128, 128), Red.Lighten(0.25).Desaturate(1).Darken(0.25))
+	assert.Equal(t, RGB(255, 0, 0), RGB(191, 64, 64).Saturate(1))
+}
+
+func TestNearest(t *testing.T) {
+	palette := []Color{Black, White, Red, Blue}
+
+	assert.Equal(t, 2, RGB(200, 30, 30).Nearest(palette))
+	assert.Equal(t, 0, RGB(20, 20, 20).Nearest(palette))
+	assert.Equal(t, -1, Red.Nearest(nil))
+	assert.Zero(t, Red.Distance(Red))
+}
@@ -0,0 +1,67 @@
+package console
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/BigJk/ramen/concolor"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// maxBatchQuads is the maximum amount of quads that fit into a single draw call,
+// because every quad needs 6 indices and ebiten limits the amount of indices
+// per call.
+const maxBatchQuads = ebiten.MaxIndicesNum / 6
+
+var whiteImage *ebiten.Image
+
+// solidImage returns a white sub-image that can be used as source for solid quads.
+// The sub-image is surrounded by white pixels, so that no neighbouring texture can
+// bleed into the quads.
+func solidImage() *ebiten.Image {
+	if whiteImage == nil {
+		whiteImage = ebiten.NewImage(3, 3)
+		whiteImage.Fill(color.White)
+	}
+	return whiteImage.SubImage(image.Rect(1, 1, 2, 2)).(*ebiten.Image)
+}
+
+// quadBatch collects textured and colored quads that are drawn with as few
+// DrawTriangles calls as possible.
+type quadBatch struct {
+	vertices []ebiten.Vertex
+	indices  []uint16
+	options  ebiten.DrawTrianglesOptions
+}
+
+// add adds a quad at the destination rectangle dst that is textured with the
+// src rectangle of the source image and tinted with col.
+func (b *quadBatch) add(dst image.Rectangle, src image.Rectangle, col concolor.Color) {
+	r, g, bl, a := col.Floats()
+	cr, cg, cb, ca := float32(r), float32(g), float32(bl), float32(a)
+
+	i := uint16(len(b.vertices))
+	b.vertices = append(b.vertices,
+		ebiten.Vertex{DstX: float32(dst.Min.X), DstY: float32(dst.Min.Y), SrcX: float32(src.Min.X), SrcY: float32(src.Min.Y), ColorR: cr, ColorG: cg, ColorB: cb, ColorA: ca},
+		ebiten.Vertex{DstX: float32(dst.Max.X), DstY: float32(dst.Min.Y), SrcX: float32(src.Max.X), SrcY: float32(src.Min.Y), ColorR: cr, ColorG: cg, ColorB: cb, ColorA: ca},
+		ebiten.Vertex{DstX: float32(dst.Min.X), DstY: float32(dst.Max.Y), SrcX: float32(src.Min.X), SrcY: float32(src.Max.Y), ColorR: cr, ColorG: cg, ColorB: cb, ColorA: ca},
+		ebiten.Vertex{DstX: float32(dst.Max.X), DstY: float32(dst.Max.Y), SrcX: float32(src.Max.X), SrcY: float32(src.Max.Y), ColorR: cr, ColorG: cg, ColorB: cb, ColorA: ca},
+	)
+	b.indices = append(b.indices, i, i+1, i+2, i+1, i+3, i+2)
+}
+
+// full returns true if no more quads can be added before the batch is flushed.
+func (b *quadBatch) full() bool {
+	return len(b.vertices)/4 >= maxBatchQuads
+}
+
+// flush draws all collected quads onto the target and resets the batch.
+func (b *quadBatch) flush(target *ebiten.Image, source *ebiten.Image) {
+	if len(b.indices) == 0 {
+		return
+	}
+
+	target.DrawTriangles(b.vertices, b.indices, source, &b.options)
+	b.vertices = b.vertices[:0]
+	b.indices = b.indices[:0]
+}
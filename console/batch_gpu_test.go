@@ -0,0 +1,129 @@
+//go:build gpu
+
+package console
+
+import (
+	"errors"
+	"image"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/BigJk/ramen/concolor"
+	"github.com/BigJk/ramen/font"
+	"github.com/BigJk/ramen/t"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+var errTestsFinished = errors.New("tests finished")
+
+// The tests in this file need a window and a graphics context, so they are only
+// built with the gpu tag: go test -tags gpu -bench . ./console
+
+// testGame keeps the ebiten loop running until all tests and benchmarks are done,
+// so that the images can actually be drawn.
+type testGame struct {
+	done chan struct{}
+}
+
+func (g *testGame) Update() error {
+	select {
+	case <-g.done:
+		return errTestsFinished
+	default:
+		return nil
+	}
+}
+
+func (g *testGame) Draw(screen *ebiten.Image) {}
+
+func (g *testGame) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return 1, 1
+}
+
+func TestMain(m *testing.M) {
+	code := make(chan int, 1)
+	done := make(chan struct{})
+
+	go func() {
+		code <- m.Run()
+		close(done)
+	}()
+
+	if err := ebiten.RunGame(&testGame{done: done}); err != nil && err != errTestsFinished {
+		panic(err)
+	}
+
+	os.Exit(<-code)
+}
+
+func newBenchmarkConsole(b *testing.B) (*Console, *ebiten.Image) {
+	con, err := New(200, 100, font.DefaultFont, "")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	rng := rand.New(rand.NewSource(0))
+	for x := 0; x < con.Width; x++ {
+		for y := 0; y < con.Height; y++ {
+			_ = con.Transform(x, y,
+				t.Char(rng.Intn(256)),
+				t.Foreground(concolor.RGB(byte(rng.Intn(256)), byte(rng.Intn(256)), byte(rng.Intn(256)))),
+				t.Background(concolor.RGB(byte(rng.Intn(256)), byte(rng.Intn(256)), byte(rng.Intn(256)))),
+			)
+		}
+	}
+
+	return con, ebiten.NewImage(con.Width*con.Font.TileWidth, con.Height*con.Font.TileHeight)
+}
+
+func TestDrawCachedLargeConsole(t *testing.T) {
+	con, err := New(200, 100, font.DefaultFont, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := ebiten.NewImage(con.Width*con.Font.TileWidth, con.Height*con.Font.TileHeight)
+
+	// A full redraw of 20000 cells needs more than one flush per batch.
+	con.drawCached(target, 0, 0, image.Rect(0, 0, con.Width, con.Height), nil, 0)
+	_ = target.At(0, 0)
+}
+
+// BenchmarkDrawCells draws every cell with a separate draw call for the
+// background and the glyph.
+func BenchmarkDrawCells(b *testing.B) {
+	con, target := newBenchmarkConsole(b)
+	clip := image.Rect(0, 0, con.Width, con.Height)
+	renderer := NewEbitenRenderer(target)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		con.drawCells(renderer, 0, 0, clip, nil)
+		_ = target.At(0, 0)
+	}
+}
+
+// BenchmarkDrawBatched re-renders every cell with the batched draw path.
+func BenchmarkDrawBatched(b *testing.B) {
+	con, target := newBenchmarkConsole(b)
+	clip := image.Rect(0, 0, con.Width, con.Height)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		con.dirtyAll = true
+		con.drawCached(target, 0, 0, clip, nil, 0)
+		_ = target.At(0, 0)
+	}
+}
+
+// BenchmarkDrawCachedUnchanged draws a console where no cell has changed.
+func BenchmarkDrawCachedUnchanged(b *testing.B) {
+	con, target := newBenchmarkConsole(b)
+	clip := image.Rect(0, 0, con.Width, con.Height)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		con.drawCached(target, 0, 0, clip, nil, 0)
+		_ = target.At(0, 0)
+	}
+}
@@ -0,0 +1,26 @@
+package console
+
+import (
+	"image"
+	"testing"
+
+	"github.com/BigJk/ramen/concolor"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuadBatchLimit(t *testing.T) {
+	var batch quadBatch
+
+	quads := 0
+	for !batch.full() {
+		batch.add(image.Rect(0, 0, 1, 1), image.Rect(0, 0, 1, 1), concolor.White)
+		quads++
+	}
+
+	// A full redraw of a 200x100 console has to be split into several draw calls
+	// that each stay within the index limit of ebiten.
+	assert.Less(t, quads, 200*100)
+	assert.LessOrEqual(t, len(batch.indices), ebiten.MaxIndicesNum)
+	assert.Equal(t, quads*4, len(batch.vertices))
+}
@@ -0,0 +1,119 @@
+package console
+
+import (
+	"image"
+
+	"github.com/BigJk/ramen"
+	"github.com/BigJk/ramen/concolor"
+)
+
+// SetKeyColor sets the background color that will be treated as transparent when
+// the console is blitted onto another console. Passing nil disables the key color.
+func (c *Console) SetKeyColor(col *concolor.Color) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if col == nil {
+		c.keyColor = nil
+	} else {
+		c.keyColor = col.P()
+	}
+}
+
+// Blit composites the cells of the area srcRect onto the dst console at the position
+// dstX, dstY. The alpha of the foreground and background of each cell is multiplied by
+// fgAlpha and bgAlpha (0 - 1), so a value of 1 for both will copy fully opaque cells.
+// Cells with a background that matches the key color of the console are skipped.
+// Parts of the area that are outside of either console are ignored. Palette colors are
+// blended with the colors they are drawn with.
+func (c *Console) Blit(dst *Console, srcRect image.Rectangle, dstX, dstY int, fgAlpha, bgAlpha float64) error {
+	srcPalette, _ := c.activePalette()
+
+	c.mtx.RLock()
+	srcRect = srcRect.Canon().Intersect(image.Rect(0, 0, c.Width, c.Height))
+
+	cells := make([][]ramen.Cell, srcRect.Dx())
+	for x := range cells {
+		cells[x] = make([]ramen.Cell, srcRect.Dy())
+		copy(cells[x], c.buffer[srcRect.Min.X+x][srcRect.Min.Y:srcRect.Max.Y])
+	}
+
+	keyColor := c.keyColor
+	c.mtx.RUnlock()
+
+	if len(cells) == 0 {
+		return nil
+	}
+
+	dst.mtx.Lock()
+	defer dst.mtx.Unlock()
+
+	// Palette references of the source cells are only kept if both consoles use
+	// the same palette.
+	dstPalette := dst.lockedPalette()
+	for x := range cells {
+		for y := range cells[x] {
+			cells[x][y].Foreground, cells[x][y].Background = resolveColors(srcPalette, cells[x][y])
+			if srcPalette != dstPalette {
+				cells[x][y].ForegroundIndex, cells[x][y].BackgroundIndex = 0, 0
+			}
+		}
+	}
+
+	for x := range cells {
+		for y := range cells[x] {
+			if dst.checkOutOfBounds(dstX+x, dstY+y) != nil {
+				continue
+			}
+
+			if keyColor != nil && cells[x][y].Background == *keyColor {
+				continue
+			}
+
+			cell := dst.buffer[dstX+x][dstY+y]
+			cell.Foreground, cell.Background = resolveColors(dstPalette, cell)
+			blitCell(&cell, cells[x][y], fgAlpha, bgAlpha)
+			dst.setCell(dstX+x, dstY+y, cell)
+		}
+	}
+
+	return nil
+}
+
+// blitCell composites the src cell onto the dst cell. This follows the
+// behaviour of libtcod's console blitting. Both cells need to have their palette
+// colors resolved. The palette indices of blended colors are cleared.
+func blitCell(dst *ramen.Cell, src ramen.Cell, fgAlpha, bgAlpha float64) {
+	fgAlpha *= float64(src.Foreground.A) / 0xff
+	bgAlpha *= float64(src.Background.A) / 0xff
+
+	if fgAlpha >= 1 && bgAlpha >= 1 {
+		*dst = src
+		return
+	}
+
+	if bgAlpha > 0 {
+		dst.Background = dst.Background.Lerp(src.Background, bgAlpha)
+		dst.BackgroundIndex = 0
+	}
+	dst.ForegroundIndex = 0
+
+	switch {
+	case isBlankChar(src.Char):
+		dst.Foreground = dst.Foreground.Lerp(src.Background, bgAlpha)
+	case isBlankChar(dst.Char):
+		dst.Char = src.Char
+		dst.Foreground = dst.Background.Lerp(src.Foreground, fgAlpha)
+	case dst.Char == src.Char:
+		dst.Foreground = dst.Foreground.Lerp(src.Foreground, fgAlpha)
+	case fgAlpha < 0.5:
+		dst.Foreground = dst.Foreground.Lerp(dst.Background, fgAlpha*2)
+	default:
+		dst.Char = src.Char
+		dst.Foreground = dst.Background.Lerp(src.Foreground, (fgAlpha-0.5)*2)
+	}
+}
+
+func isBlankChar(char int) bool {
+	return char == 0 || char == ' '
+}
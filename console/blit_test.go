@@ -0,0 +1,113 @@
+package console
+
+import (
+	"image"
+	"testing"
+
+	"github.com/BigJk/ramen"
+	"github.com/BigJk/ramen/concolor"
+	"github.com/BigJk/ramen/t"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlit(test *testing.T) {
+	srcFg, srcBg := concolor.RGB(255, 255, 0), concolor.RGB(0, 0, 200)
+	dstFg, dstBg := concolor.RGB(100, 100, 100), concolor.RGB(0, 50, 0)
+	key := concolor.RGB(0, 255, 0)
+
+	// The foreground is blended after the background, so it fades to the new one.
+	halfBg := dstBg.Lerp(srcBg, 0.5)
+
+	type cellCheck struct {
+		x, y int
+		cell ramen.Cell
+	}
+
+	cases := []struct {
+		name  string
+		blit  func(src, dst *Console) error
+		rows  []string
+		cells []cellCheck
+	}{
+		{"FullCopy", func(src, dst *Console) error {
+			return src.Blit(dst, image.Rect(0, 0, 3, 2), 1, 1, 1, 1)
+		}, []string{"01234", "5ABC9", "aDEFe"}, []cellCheck{
+			{1, 1, ramen.Cell{Char: 'A', Foreground: srcFg, Background: srcBg}},
+		}},
+		{"PartialForegroundAlpha", func(src, dst *Console) error {
+			if err := src.Blit(dst, image.Rect(0, 0, 1, 1), 0, 0, 0.25, 0.5); err != nil {
+				return err
+			}
+			return src.Blit(dst, image.Rect(0, 0, 1, 1), 1, 0, 0.75, 0.5)
+		}, []string{"0A234", "56789", "abcde"}, []cellCheck{
+			// Below half of the alpha the old char fades to the background.
+			{0, 0, ramen.Cell{Char: '0', Foreground: dstFg.Lerp(halfBg, 0.5), Background: halfBg}},
+			// Above it the new char fades in from the background.
+			{1, 0, ramen.Cell{Char: 'A', Foreground: halfBg.Lerp(srcFg, 0.5), Background: halfBg}},
+		}},
+		{"PartialBackgroundAlpha", func(src, dst *Console) error {
+			return src.Blit(dst, image.Rect(0, 0, 1, 1), 0, 0, 1, 0.5)
+		}, []string{"A1234", "56789", "abcde"}, []cellCheck{
+			{0, 0, ramen.Cell{Char: 'A', Foreground: srcFg, Background: halfBg}},
+		}},
+		{"BlankSourceChar", func(src, dst *Console) error {
+			if err := src.Transform(0, 0, t.Char(' ')); err != nil {
+				return err
+			}
+			return src.Blit(dst, image.Rect(0, 0, 1, 1), 0, 0, 1, 0.5)
+		}, []string{"01234", "56789", "abcde"}, []cellCheck{
+			// The old char is tinted with the background of the blank cell.
+			{0, 0, ramen.Cell{Char: '0', Foreground: dstFg.Lerp(srcBg, 0.5), Background: halfBg}},
+		}},
+		{"KeyColor", func(src, dst *Console) error {
+			if err := src.Transform(1, 0, t.Background(key)); err != nil {
+				return err
+			}
+			src.SetKeyColor(&key)
+			return src.Blit(dst, image.Rect(0, 0, 3, 2), 0, 0, 1, 1)
+		}, []string{"A1C34", "DEF89", "abcde"}, []cellCheck{
+			{1, 0, ramen.Cell{Char: '1', Foreground: dstFg, Background: dstBg}},
+		}},
+		{"ClippedDestination", func(src, dst *Console) error {
+			return src.Blit(dst, image.Rect(0, 0, 3, 2), 3, 2, 1, 1)
+		}, []string{"01234", "56789", "abcAB"}, nil},
+		{"ClippedSource", func(src, dst *Console) error {
+			return src.Blit(dst, image.Rect(1, 0, 10, 10), 3, 1, 1, 1)
+		}, []string{"01234", "567BC", "abcEF"}, nil},
+		{"EmptySource", func(src, dst *Console) error {
+			return src.Blit(dst, image.Rect(5, 5, 10, 10), 0, 0, 1, 1)
+		}, []string{"01234", "56789", "abcde"}, nil},
+		{"OverlappingSelf", func(src, dst *Console) error {
+			return dst.Blit(dst, image.Rect(0, 0, 3, 2), 1, 0, 1, 1)
+		}, []string{"00124", "55679", "abcde"}, nil},
+	}
+
+	for _, c := range cases {
+		test.Run(c.name, func(test *testing.T) {
+			src, err := New(3, 2, newTestFont(), "")
+			if !assert.NoError(test, err) {
+				return
+			}
+
+			dst, err := New(5, 3, newTestFont(), "")
+			if !assert.NoError(test, err) {
+				return
+			}
+
+			assert.NoError(test, src.TransformAll(t.Foreground(srcFg), t.Background(srcBg)))
+			src.Print(0, 0, "ABC\nDEF")
+			assert.NoError(test, dst.TransformAll(t.Foreground(dstFg), t.Background(dstBg)))
+			dst.Print(0, 0, "01234\n56789\nabcde")
+
+			assert.NoError(test, c.blit(src, dst))
+			assert.Equal(test, c.rows, charRows(test, dst))
+
+			for _, check := range c.cells {
+				cell, err := dst.Get(check.x, check.y)
+				if assert.NoError(test, err) {
+					assert.Equal(test, check.cell, cell, "cell %d,%d", check.x, check.y)
+				}
+			}
+		})
+	}
+}
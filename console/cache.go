@@ -0,0 +1,141 @@
+package console
+
+import (
+	"image"
+
+	"github.com/BigJk/ramen"
+	"github.com/BigJk/ramen/concolor"
+	"github.com/BigJk/ramen/t"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// setCell changes the cell at the given position and marks it as dirty if the
+// value has changed. The write lock of the console needs to be held.
+func (c *Console) setCell(x, y int, cell ramen.Cell) {
+	if c.buffer[x][y] == cell {
+		return
+	}
+
+	c.buffer[x][y] = cell
+	c.dirty[x*c.Height+y] = true
+}
+
+// transformCell applies the transformers to the cell at the given position that is
+// part of the transformed area. The write lock of the console needs to be held.
+func (c *Console) transformCell(x, y int, area t.Area, transformer ...t.Transformer) error {
+	cell := c.buffer[x][y]
+
+	// Transformers like blending work with the current colors of the cell, so
+	// palette references are resolved to the colors they are drawn with.
+	if cell.ForegroundIndex != 0 || cell.BackgroundIndex != 0 {
+		cell.Foreground, cell.Background = resolveColors(c.lockedPalette(), cell)
+	}
+
+	for i := range transformer {
+		if err := t.Apply(transformer[i], &cell, x, y, area); err != nil {
+			return err
+		}
+	}
+
+	c.setCell(x, y, cell)
+	return nil
+}
+
+// drawCells draws all cells inside the clipping area with the renderer.
+func (c *Console) drawCells(renderer Renderer, originX, originY int, clip image.Rectangle, palette *concolor.Palette) {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	for x := range c.buffer {
+		for y := range c.buffer[x] {
+			_, background := resolveColors(palette, c.buffer[x][y])
+			if background.A == 0 || !(image.Point{X: originX + x, Y: originY + y}).In(clip) {
+				continue
+			}
+
+			renderer.DrawBackground((originX+x)*c.Font.TileWidth, (originY+y)*c.Font.TileHeight, c.Font.TileWidth, c.Font.TileHeight, background)
+		}
+	}
+
+	for x := range c.buffer {
+		for y := range c.buffer[x] {
+			if !(image.Point{X: originX + x, Y: originY + y}).In(clip) {
+				continue
+			}
+
+			foreground, _ := resolveColors(palette, c.buffer[x][y])
+			renderer.DrawChar(c.Font, c.buffer[x][y].Char, (originX+x)*c.Font.TileWidth, (originY+y)*c.Font.TileHeight, foreground)
+		}
+	}
+}
+
+// eachDirtyCell calls fn for every cell that changed since the last call and
+// resets the dirty flags. The write lock of the console needs to be held.
+func (c *Console) eachDirtyCell(fn func(x, y int)) {
+	for x := range c.buffer {
+		for y := range c.buffer[x] {
+			if !c.dirtyAll && !c.dirty[x*c.Height+y] {
+				continue
+			}
+			c.dirty[x*c.Height+y] = false
+			fn(x, y)
+		}
+	}
+
+	c.dirtyAll = false
+}
+
+// drawCached re-renders all dirty cells into the cached image of the console and
+// draws the visible part of the cache onto the target. The dirty cells are rendered
+// as two batches of quads, one for the backgrounds and one for the glyphs, so that
+// only a handful of draw calls are needed regardless of the console size.
+func (c *Console) drawCached(target *ebiten.Image, originX, originY int, clip image.Rectangle, palette *concolor.Palette, paletteGen uint64) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	tw, th := c.Font.TileWidth, c.Font.TileHeight
+
+	if c.cache == nil {
+		c.cache = ebiten.NewImage(c.Width*tw, c.Height*th)
+		c.dirtyAll = true
+	}
+
+	if c.drawnGen != paletteGen {
+		c.drawnGen = paletteGen
+		c.dirtyAll = true
+	}
+
+	solid := solidImage()
+	backgrounds, glyphs := &c.backgrounds, &c.glyphs
+	backgrounds.options.CompositeMode = ebiten.CompositeModeCopy
+
+	c.eachDirtyCell(func(x, y int) {
+		cell := c.buffer[x][y]
+		foreground, background := resolveColors(palette, cell)
+		dst := image.Rect(x*tw, y*th, (x+1)*tw, (y+1)*th)
+
+		// Backgrounds are copied instead of blended, which also clears
+		// whatever was rendered in the cell before.
+		backgrounds.add(dst, solid.Bounds(), background)
+
+		if src, ok := c.Font.ToSubRect(cell.Char); ok {
+			if c.Font.IsTile(cell.Char) {
+				foreground = concolor.White
+			}
+			glyphs.add(dst, src, foreground)
+		}
+
+		if backgrounds.full() || glyphs.full() {
+			backgrounds.flush(c.cache, solid)
+			glyphs.flush(c.cache, c.Font.Image)
+		}
+	})
+
+	backgrounds.flush(c.cache, solid)
+	glyphs.flush(c.cache, c.Font.Image)
+
+	visible := clip.Sub(image.Pt(originX, originY))
+	op := ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(clip.Min.X*tw), float64(clip.Min.Y*th))
+	target.DrawImage(c.cache.SubImage(image.Rect(visible.Min.X*tw, visible.Min.Y*th, visible.Max.X*tw, visible.Max.Y*th)).(*ebiten.Image), &op)
+}
@@ -0,0 +1,51 @@
+package console
+
+import (
+	"image"
+	"testing"
+
+	"github.com/BigJk/ramen/t"
+	"github.com/stretchr/testify/assert"
+)
+
+// dirtyCells returns the cells that would be drawn again by the next draw and
+// resets them like the draw does.
+func dirtyCells(con *Console) []image.Point {
+	con.mtx.Lock()
+	defer con.mtx.Unlock()
+
+	var cells []image.Point
+	con.eachDirtyCell(func(x, y int) {
+		cells = append(cells, image.Pt(x, y))
+	})
+	return cells
+}
+
+func TestDirtyCells(test *testing.T) {
+	con, err := New(3, 2, newTestFont(), "")
+	if !assert.NoError(test, err) {
+		return
+	}
+
+	// A new console is drawn completely once.
+	assert.Len(test, dirtyCells(con), 6)
+	assert.Empty(test, dirtyCells(con))
+
+	assert.NoError(test, con.Transform(1, 1, t.Char('a')))
+	assert.Equal(test, []image.Point{{1, 1}}, dirtyCells(con))
+	assert.Empty(test, dirtyCells(con))
+
+	// Cells that already have the new value aren't marked.
+	assert.NoError(test, con.TransformAll(t.Char('a')))
+	assert.Equal(test, []image.Point{{0, 0}, {0, 1}, {1, 0}, {2, 0}, {2, 1}}, dirtyCells(con))
+
+	assert.NoError(test, con.Clear(1, 0, 2, 1))
+	assert.Equal(test, []image.Point{{1, 0}, {2, 0}}, dirtyCells(con))
+
+	assert.NoError(test, con.Clear(1, 0, 2, 1))
+	assert.Empty(test, dirtyCells(con))
+
+	// Resizing draws the whole console again.
+	assert.NoError(test, con.Resize(2, 2))
+	assert.Len(test, dirtyCells(con), 4)
+}
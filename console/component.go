@@ -80,14 +80,16 @@ func (cb *ComponentBase) Show(value bool) {
 	cb.show = value
 }
 
-// IsFocused returns true if the component is active, which means it was clicked on.
+// IsFocused returns true if the component is active, which means it was clicked on or
+// focused with the keyboard.
 func (cb *ComponentBase) IsFocused() bool {
 	cb.mtx.Lock()
 	defer cb.mtx.Unlock()
 	return cb.focus
 }
 
-// SetFocus adds or remove focus from component.
+// SetFocus adds or remove focus from component. Prefer Console.Focus, which also
+// removes the focus from the other components of the console.
 func (cb *ComponentBase) SetFocus(value bool) {
 	cb.mtx.Lock()
 	defer cb.mtx.Unlock()
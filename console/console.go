@@ -3,6 +3,7 @@ package console
 
 import (
 	"fmt"
+	"image"
 	"math"
 	"sync"
 
@@ -14,7 +15,6 @@ import (
 	"github.com/BigJk/ramen/t"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 )
 
 var emptyCell = ramen.Cell{
@@ -33,24 +33,61 @@ type Console struct {
 	parent       *Console
 	x            int
 	y            int
-	priority     int
+	priority     int // guarded by the lock of the parent
 	isSubConsole bool
+	isOffscreen  bool
+	hidden       bool
 
-	mtx    sync.RWMutex
-	buffer [][]ramen.Cell
+	view    *Console
+	cameraX int
+	cameraY int
+	worldX  int
+	worldY  int
+
+	scale      float64
+	autoResize bool
+
+	mtx      sync.RWMutex
+	buffer   [][]ramen.Cell
+	keyColor *concolor.Color
+
+	palette    *concolor.Palette
+	paletteGen uint64
+
+	dirty       []bool
+	dirtyAll    bool
+	drawnGen    uint64
+	cache       *ebiten.Image
+	backgrounds quadBatch
+	glyphs      quadBatch
 
 	mouseX int
 	mouseY int
 
-	components map[string]Component
+	compMtx        sync.Mutex
+	components     []Component
+	zIndex         map[string]int
+	compPasses     int
+	pending        []func()
+	pendingMounted map[string]Component
 
+	queue eventQueue
+	focus focusState
+	input Input
+
+	eventHook      func(event *Event) error
 	tickHook       func(timeElapsed float64) error
 	preRenderHook  func(screen *ebiten.Image, timeElapsed float64) error
 	postRenderHook func(screen *ebiten.Image, timeElapsed float64) error
+	resizeHook     func(parentWidth, parentHeight int) error
 }
 
 // New creates a new console.
 func New(width, height int, font *font.Font, title string) (*Console, error) {
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("console needs a size greater than zero")
+	}
+
 	buf := make([][]ramen.Cell, width)
 	for x := range buf {
 		buf[x] = make([]ramen.Cell, height)
@@ -59,33 +96,59 @@ func New(width, height int, font *font.Font, title string) (*Console, error) {
 		}
 	}
 
-	lines := make([]*ebiten.Image, width)
-	for i := range lines {
-		lines[i] = ebiten.NewImage(font.TileWidth, height*font.TileHeight)
-	}
-
 	return &Console{
-		Title:       title,
-		Width:       width,
-		Height:      height,
-		Font:        font,
-		SubConsoles: make([]*Console, 0),
-		buffer:      buf,
-		components:  map[string]Component{},
+		Title:          title,
+		Width:          width,
+		Height:         height,
+		Font:           font,
+		SubConsoles:    make([]*Console, 0),
+		buffer:         buf,
+		dirty:          make([]bool, width*height),
+		dirtyAll:       true,
+		zIndex:         map[string]int{},
+		pendingMounted: map[string]Component{},
+		input:          EbitenInput{},
 	}, nil
 }
 
+// NewOffscreen creates a new console that is not attached to a window. It can't be
+// started, but it can be used as a buffer that is blitted onto other consoles or as
+// the source of a viewport. Off-screen consoles can be bigger than the window.
+func NewOffscreen(width, height int, font *font.Font) (*Console, error) {
+	con, err := New(width, height, font, "")
+	if err != nil {
+		return nil, err
+	}
+
+	con.isOffscreen = true
+	return con, nil
+}
+
 // Update proceeds the game state and is called every tick (1/60 [s] by default).
 // This is an ebiten function. Don't call it yourself!
 func (c *Console) Update() error {
-	c.mtx.RLock()
-	mx, my := ebiten.CursorPosition()
-	c.propagateMousePosition(mx/c.Font.TileWidth, my/c.Font.TileHeight)
-	c.propagateComponentUpdates(c.elapsedTPS())
-	c.mtx.RUnlock()
+	input := c.Input()
+	input.Update()
+
+	mx, my := input.CursorPosition()
+	cx, cy := floorDiv(mx, c.Font.TileWidth), floorDiv(my, c.Font.TileHeight)
+
+	c.propagateMousePosition(cx, cy, mx >= 0 && my >= 0)
+
+	c.collectEvents(input, cx, cy)
+	if err := c.dispatchEvents(); err != nil {
+		return err
+	}
+
+	timeElapsed := c.elapsedTPS()
+	if clock, ok := input.(InputClock); ok {
+		timeElapsed = clock.TimeElapsed(timeElapsed)
+	}
+
+	c.propagateComponentUpdates(timeElapsed)
 
 	if c.tickHook != nil {
-		if err := c.tickHook(c.elapsedTPS()); err != nil {
+		if err := c.tickHook(timeElapsed); err != nil {
 			return err
 		}
 	}
@@ -101,21 +164,46 @@ func (c *Console) Draw(screen *ebiten.Image) {
 
 // Layout returns size of drawable area inside window. This will be stretched to window sized.
 // This is an ebiten function. Don't call it yourself!
+// If auto resize is enabled the console will be resized to fill the whole window.
 func (c *Console) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
-	return c.Width * c.Font.TileWidth, c.Height * c.Font.TileHeight
+	if c.autoResize {
+		c.resizeToWindow(outsideWidth, outsideHeight)
+	}
+	width, height := c.size()
+	return width * c.Font.TileWidth, height * c.Font.TileHeight
 }
 
 // Start will open the console window with the given scale.
 func (c *Console) Start(scale float64) error {
-	if c.isSubConsole {
+	if c.isSubConsole || c.isOffscreen {
 		return fmt.Errorf("only the main console can be started")
 	}
 
-	ebiten.SetWindowSize(int(float64(c.Width*c.Font.TileWidth)*scale), int(float64(c.Height*c.Font.TileHeight)*scale))
+	c.scale = scale
+
+	width, height := c.size()
+	ebiten.SetWindowSize(int(float64(width*c.Font.TileWidth)*scale), int(float64(height*c.Font.TileHeight)*scale))
 	ebiten.SetWindowTitle(c.Title)
 	return ebiten.RunGame(c)
 }
 
+// Render draws the console including all sub-consoles and components with the
+// given renderer. Hooks are not triggered.
+func (c *Console) Render(renderer Renderer, timeElapsed float64) {
+	width, height := c.size()
+	c.draw(renderer, timeElapsed, 0, 0, image.Rect(0, 0, width, height))
+}
+
+// RenderImage draws the console including all sub-consoles and components into a new
+// RGBA image. This doesn't need a graphics context, so it can also be used in headless
+// environments to create snapshots of the console.
+func (c *Console) RenderImage() *image.RGBA {
+	width, height := c.size()
+	renderer := NewImageRenderer(width*c.Font.TileWidth, height*c.Font.TileHeight)
+	c.Render(renderer, 0)
+	return renderer.Image
+}
+
 // SetTickHook will apply a hook that gets triggered every tick, even if drawing is skipped in this tick.
 // This is a good place for game logic as it runs disconnected from the fps.
 func (c *Console) SetTickHook(hook func(timeElapsed float64) error) error {
@@ -152,55 +240,69 @@ func (c *Console) SetPriority(priority int) error {
 	if !c.isSubConsole {
 		return fmt.Errorf("priority of the main console can't be changed")
 	}
+
+	c.parent.mtx.Lock()
+	defer c.parent.mtx.Unlock()
+
 	c.priority = priority
-	c.parent.sortSubConsoles()
+	c.parent.sortLockedSubConsoles()
 	return nil
 }
 
 // AddComponent adds a component that should be updated and rendered to the console.
-//
-// Attention: Don't use this function inside a callback from a component as this will
-// create a deadlock!
+// The component is placed on top of the other components with a z-index of 0. If a
+// component with the same id is already mounted it is replaced.
+// This is safe to use from component callbacks. If the components of the console
+// are currently updated or drawn the component is added after that.
 func (c *Console) AddComponent(component Component) {
-	c.mtx.Lock()
-	defer c.mtx.Unlock()
+	c.compMtx.Lock()
+	defer c.compMtx.Unlock()
+
+	c.recordPending(component.ID(), component)
+	c.mutateComponents(func() {
+		if i := c.componentIndex(component.ID()); i >= 0 {
+			c.components[i] = component
+			return
+		}
 
-	c.components[component.ID()] = component
+		c.insertComponent(component, 0, true)
+	})
 }
 
 // RemoveComponent removes a component from the console.
-//
-// Attention: Don't use this function inside a callback from a component as this will
-// create a deadlock!
+// This is safe to use from component callbacks. If the components of the console
+// are currently updated or drawn the component is removed after that.
 func (c *Console) RemoveComponent(component Component) {
-	c.mtx.Lock()
-	defer c.mtx.Unlock()
+	c.compMtx.Lock()
+	defer c.compMtx.Unlock()
 
-	delete(c.components, component.ID())
+	c.recordPending(component.ID(), nil)
+	c.mutateComponents(func() {
+		c.removeComponent(component.ID())
+	})
 }
 
-// HasComponent checks if component is mounted to the console.
-//
-// Attention: Don't use this function inside a callback from a component as this will
-// create a deadlock!
+// HasComponent checks if component is mounted to the console. Components that are
+// added or removed after the running update or draw pass are already taken into account.
 func (c *Console) HasComponent(component Component) bool {
-	c.mtx.Lock()
-	defer c.mtx.Unlock()
+	c.compMtx.Lock()
+	defer c.compMtx.Unlock()
 
-	_, ok := c.components[component.ID()]
-	return ok
+	return c.mountedComponent(component.ID()) != nil
 }
 
 // CreateSubConsole creates a new sub-console.
 func (c *Console) CreateSubConsole(x, y, width, height int) (*Console, error) {
+	c.mtx.Lock()
+
 	if x < 0 || y < 0 || x+width > c.Width || y+height > c.Height || width <= 0 || height <= 0 {
+		c.mtx.Unlock()
 		return nil, fmt.Errorf("sub-console is out of bounds")
 	}
 
-	c.mtx.Lock()
-
 	sub, err := New(width, height, c.Font, "")
 	if err != nil {
+		c.mtx.Unlock()
 		return nil, err
 	}
 
@@ -249,14 +351,25 @@ func (c *Console) Clear(x, y, width, height int) error {
 
 // TransformAll applies the given transformers to all cells in the console.
 func (c *Console) TransformAll(transformer ...t.Transformer) error {
-	return c.TransformArea(0, 0, c.Width, c.Height, transformer...)
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.transformArea(0, 0, c.Width, c.Height, transformer...)
 }
 
 // TransformArea applies the given transformers to all cells in the given area.
+// Transformers that implement t.PositionTransformer get the position of each cell
+// relative to the area, which makes gradients and patterns possible.
 func (c *Console) TransformArea(x, y, width, height int, transformer ...t.Transformer) error {
 	c.mtx.Lock()
 	defer c.mtx.Unlock()
 
+	return c.transformArea(x, y, width, height, transformer...)
+}
+
+// transformArea is TransformArea without locking the console.
+func (c *Console) transformArea(x, y, width, height int, transformer ...t.Transformer) error {
+	area := t.Area{X: x, Y: y, Width: width, Height: height}
 	for px := 0; px < width; px++ {
 		for py := 0; py < height; py++ {
 			if err := c.checkOutOfBounds(px+x, py+y); err != nil {
@@ -264,16 +377,9 @@ func (c *Console) TransformArea(x, y, width, height int, transformer ...t.Transf
 			}
 
 			if len(transformer) == 0 {
-				if c.buffer[px+x][py+y] != emptyCell {
-					c.buffer[px+x][py+y] = emptyCell
-				}
-			} else {
-				for i := range transformer {
-					err := transformer[i].Transform(&c.buffer[px+x][py+y])
-					if err != nil {
-						return err
-					}
-				}
+				c.setCell(px+x, py+y, emptyCell)
+			} else if err := c.transformCell(px+x, py+y, area, transformer...); err != nil {
+				return err
 			}
 		}
 	}
@@ -282,27 +388,152 @@ func (c *Console) TransformArea(x, y, width, height int, transformer ...t.Transf
 }
 
 // Transform transforms a cell. This can be used to change the character, foreground and
-// background of a cell or apply custom transformers onto a cell.
+// background of a cell or apply custom transformers onto a cell. Transformers that
+// implement t.PositionTransformer get the cell itself as area, so use TransformArea
+// or PrintWith to apply gradients over multiple cells.
 func (c *Console) Transform(x, y int, transformer ...t.Transformer) error {
 	if len(transformer) == 0 {
 		return fmt.Errorf("no transformer given")
-	} else if err := c.checkOutOfBounds(x, y); err != nil {
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if err := c.checkOutOfBounds(x, y); err != nil {
 		return err
 	}
 
+	return c.transformCell(x, y, t.Area{X: x, Y: y, Width: 1, Height: 1}, transformer...)
+}
+
+// Scroll moves the content of the whole console by dx, dy cells. Cells that are
+// moved out of the console are dropped and the vacated cells are set to fill.
+func (c *Console) Scroll(dx, dy int, fill ramen.Cell) error {
 	c.mtx.Lock()
 	defer c.mtx.Unlock()
 
-	for i := range transformer {
-		err := transformer[i].Transform(&c.buffer[x][y])
-		if err != nil {
-			return err
+	return c.scrollArea(0, 0, c.Width, c.Height, dx, dy, fill)
+}
+
+// ScrollArea moves the content of the given area by dx, dy cells. Cells that are
+// moved out of the area are dropped and the vacated cells are set to fill.
+func (c *Console) ScrollArea(x, y, width, height, dx, dy int, fill ramen.Cell) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.scrollArea(x, y, width, height, dx, dy, fill)
+}
+
+// scrollArea is ScrollArea without locking the console.
+func (c *Console) scrollArea(x, y, width, height, dx, dy int, fill ramen.Cell) error {
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+
+	if err := c.checkOutOfBounds(x, y); err != nil {
+		return err
+	} else if err := c.checkOutOfBounds(x+width-1, y+height-1); err != nil {
+		return err
+	}
+
+	// Iterate against the scroll direction so that every cell is read before
+	// it gets overwritten.
+	for i := 0; i < width; i++ {
+		px := i
+		if dx > 0 {
+			px = width - 1 - i
+		}
+
+		for j := 0; j < height; j++ {
+			py := j
+			if dy > 0 {
+				py = height - 1 - j
+			}
+
+			sx, sy := px-dx, py-dy
+			if sx >= 0 && sy >= 0 && sx < width && sy < height {
+				c.setCell(x+px, y+py, c.buffer[x+sx][y+sy])
+			} else {
+				c.setCell(x+px, y+py, fill)
+			}
 		}
 	}
 
 	return nil
 }
 
+// Get returns a copy of the cell at the given position.
+func (c *Console) Get(x, y int) (ramen.Cell, error) {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	if err := c.checkOutOfBounds(x, y); err != nil {
+		return ramen.Cell{}, err
+	}
+
+	return c.buffer[x][y], nil
+}
+
+// Cells returns a copy of all the cells in the console. The cells are indexed
+// by [x][y].
+func (c *Console) Cells() [][]ramen.Cell {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	cells, _ := c.region(0, 0, c.Width, c.Height)
+	return cells
+}
+
+// Region returns a copy of the cells in the given area. The cells are indexed
+// by [x][y] relative to the area.
+func (c *Console) Region(x, y, width, height int) ([][]ramen.Cell, error) {
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("region has no size")
+	}
+
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.region(x, y, width, height)
+}
+
+// region is Region without locking the console.
+func (c *Console) region(x, y, width, height int) ([][]ramen.Cell, error) {
+	if err := c.checkOutOfBounds(x, y); err != nil {
+		return nil, err
+	} else if err := c.checkOutOfBounds(x+width-1, y+height-1); err != nil {
+		return nil, err
+	}
+
+	region := make([][]ramen.Cell, width)
+	for px := range region {
+		region[px] = make([]ramen.Cell, height)
+		copy(region[px], c.buffer[x+px][y:y+height])
+	}
+
+	return region, nil
+}
+
+// EachCell calls fn for every cell that is not empty, column by column from the
+// left to the right. Returning false from fn stops the iteration. The console is locked while iterating, so fn must not
+// modify the console.
+func (c *Console) EachCell(fn func(x, y int, cell ramen.Cell) bool) {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	for x := range c.buffer {
+		for y := range c.buffer[x] {
+			if c.buffer[x][y] == emptyCell {
+				continue
+			}
+
+			if !fn(x, y, c.buffer[x][y]) {
+				return
+			}
+		}
+	}
+}
+
 // Print prints a text onto the console. To give the text a different foreground or
 // background color use transformer. This function also supports inlined color
 // definitions.
@@ -324,82 +555,146 @@ func (c *Console) PrintBounded(x, y, width, height int, text string, transformer
 // To give the text a different foreground or background color use transformer.
 // This function also supports inlined color definitions.
 func (c *Console) PrintBoundedOffset(x, y, width, height, sy int, text string, transformer ...t.Transformer) int {
-	cleaned, colors := ParseColoredText(text)
+	return c.PrintWith(x, y, width, height, PrintOptions{Offset: sy}, text, transformer...)
+}
 
-	line := 0
-	linePos := 0
-	for i, val := range cleaned {
-		if cleaned[i] == '\n' || width > 0 && linePos >= width {
-			y++
-			linePos = 0
-			line++
+// PrintWith prints a text onto the console that is bounded by a width and height and
+// laid out with the given options. It returns the amount of lines the text needs,
+// excluding the lines skipped by the offset.
+// If you set width or height to <= 0 this bound won't have a limit.
+// To give the text a different foreground or background color use transformer.
+// Transformers that implement t.PositionTransformer get the extent of the printed
+// text as area, so gradients span the whole text.
+// This function also supports inlined color definitions.
+func (c *Console) PrintWith(x, y, width, height int, options PrintOptions, text string, transformer ...t.Transformer) int {
+	spans := ParseMarkup(text)
+
+	lines := layoutText(spans, width, options)
+	if options.Offset > len(lines) {
+		options.Offset = len(lines)
+	} else if options.Offset < 0 {
+		options.Offset = 0
+	}
+	visible := lines[options.Offset:]
+	if height > 0 && len(visible) > height {
+		visible = visible[:height]
+	}
 
-			if cleaned[i] == '\n' {
-				continue
-			}
+	if height > 0 {
+		switch options.VerticalAlign {
+		case AlignMiddle:
+			y += (height - len(visible)) / 2
+		case AlignBottom:
+			y += height - len(visible)
 		}
+	}
 
-		if x+linePos >= c.Width || height > 0 && line >= height {
-			continue
+	boxWidth := width
+	if boxWidth <= 0 {
+		boxWidth = maxLineWidth(lines)
+	}
+
+	// Positional transformers like gradients are applied over the extent of
+	// the printed text.
+	positions := make([][]int, len(visible))
+	area := t.Area{X: x, Y: y, Height: len(visible)}
+	minX, maxX := boxWidth, -1
+	for line := range visible {
+		positions[line] = alignLine(visible[line], boxWidth, options.Align)
+		for _, px := range positions[line] {
+			if px < minX {
+				minX = px
+			}
+			if px > maxX {
+				maxX = px
+			}
 		}
+	}
+	if maxX >= minX {
+		area.X += minX
+		area.Width = maxX - minX + 1
+	}
 
-		if line >= sy {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for line := range visible {
+		for i, char := range visible[line].chars {
 			trans := transformer
-			trans = append(trans, t.Char(int(val)))
-			trans = append(trans, colors.GetCurrentTransformer(i)...)
+			if char.glyph {
+				trans = append(trans, t.Char(int(char.char)))
+			} else {
+				trans = append(trans, t.Char(c.Font.MapRune(char.char)))
+			}
+			trans = append(trans, spans[char.span].Transformer()...)
 
-			c.Transform(linePos+x, y-sy, trans...)
-		}
+			// The cells that justified lines insert in front of a space are
+			// written like the space itself.
+			if i > 0 {
+				for px := positions[line][i-1] + 1; px < positions[line][i]; px++ {
+					_ = c.transformClipped(x+px, y+line, area, trans...)
+				}
+			}
 
-		linePos++
+			_ = c.transformClipped(x+positions[line][i], y+line, area, trans...)
+		}
 	}
 
-	return line + 1 - sy
+	return len(lines) - options.Offset
 }
 
 // CalcTextHeight pre-calculates the height a text will need.
 func (c *Console) CalcTextHeight(width, height int, text string) int {
-	cleaned, _ := ParseColoredText(text)
-
-	line := 0
-	linePos := 0
-	for i := range cleaned {
-		if cleaned[i] == '\n' || width > 0 && linePos >= width {
-			linePos = 0
-			line++
-
-			if cleaned[i] == '\n' {
-				continue
-			}
-		}
-
-		if height > 0 && line >= height {
-			continue
-		}
+	return c.CalcTextHeightWith(width, height, PrintOptions{}, text)
+}
 
-		linePos++
+// CalcTextHeightWith pre-calculates the height a text will need when it is printed
+// with the given options.
+func (c *Console) CalcTextHeightWith(width, height int, options PrintOptions, text string) int {
+	lines := len(layoutText(ParseMarkup(text), width, options))
+	if options.Offset > lines {
+		return 0
+	} else if options.Offset < 0 {
+		return lines
 	}
-
-	return line + 1
+	return lines - options.Offset
 }
 
 // MousePosition returns the cell that the mouse cursor is currently in. If it returns
 // (-1, -1) the mouse cursor is currently not in the console.
 func (c *Console) MousePosition() (int, int) {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
 	return c.mouseX, c.mouseY
 }
 
 // MouseInArea checks if the mouse cursor is currently in the given area.
 func (c *Console) MouseInArea(x, y, width, height int) bool {
-	return c.mouseX >= x && c.mouseY >= y && c.mouseX < x+width && c.mouseY < y+height
+	mouseX, mouseY := c.MousePosition()
+	return mouseX >= x && mouseY >= y && mouseX < x+width && mouseY < y+height
 }
 
 func (c *Console) sortSubConsoles() {
 	c.mtx.Lock()
+	c.sortLockedSubConsoles()
+	c.mtx.Unlock()
+}
+
+// sortLockedSubConsoles sorts the sub-consoles by their priority. The lock of the
+// console needs to be held.
+func (c *Console) sortLockedSubConsoles() {
 	sort.SliceStable(c.SubConsoles, func(i, j int) bool {
 		return c.SubConsoles[i].priority < c.SubConsoles[j].priority
 	})
-	c.mtx.Unlock()
+}
+
+// size returns the size of the console in cells.
+func (c *Console) size() (int, int) {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.Width, c.Height
 }
 
 func (c *Console) checkOutOfBounds(x, y int) error {
@@ -409,76 +704,97 @@ func (c *Console) checkOutOfBounds(x, y int) error {
 	return nil
 }
 
-func (c *Console) draw(screen *ebiten.Image, timeElapsed float64, offsetX, offsetY int) {
-	for id := range c.components {
-		if c.components[id].ShouldDraw() {
-			c.components[id].Draw(c, timeElapsed)
-		}
+func (c *Console) draw(renderer Renderer, timeElapsed float64, offsetX, offsetY int, clip image.Rectangle) {
+	c.mtx.RLock()
+	originX, originY := offsetX+c.x, offsetY+c.y
+	clip = clip.Intersect(image.Rect(originX, originY, originX+c.Width, originY+c.Height))
+	hidden := c.hidden
+	c.mtx.RUnlock()
+
+	if hidden || clip.Empty() {
+		return
 	}
 
-	c.mtx.RLock()
-	for x := range c.buffer {
-		for y := range c.buffer[x] {
-			if c.buffer[x][y].Background.A == 0 {
-				continue
-			}
+	c.syncView()
 
-			ebitenutil.DrawRect(screen, float64((offsetX+c.x+x)*c.Font.TileWidth), float64((offsetY+c.y+y)*c.Font.TileHeight), float64(c.Font.TileWidth), float64(c.Font.TileHeight), c.buffer[x][y].Background)
+	c.beginComponentPass()
+	components := c.componentList()
+	for i := range components {
+		if components[i].ShouldDraw() {
+			components[i].Draw(c, timeElapsed)
 		}
 	}
+	c.endComponentPass()
 
-	for x := range c.buffer {
-		for y := range c.buffer[x] {
-			charImage := c.Font.ToSubImage(c.buffer[x][y].Char)
-			if charImage != nil {
-				op := ebiten.DrawImageOptions{}
-				if !c.Font.IsTile(c.buffer[x][y].Char) {
-					op.ColorM.Scale(c.buffer[x][y].Foreground.Floats())
-				}
-				op.GeoM.Translate(float64((offsetX+c.x+x)*c.Font.TileWidth), float64((offsetY+c.y+y)*c.Font.TileHeight))
-				screen.DrawImage(charImage, &op)
-			}
-		}
+	palette, paletteGen := c.activePalette()
+	if target, ok := renderer.(*EbitenRenderer); ok {
+		c.drawCached(target.Target, originX, originY, clip, palette, paletteGen)
+	} else {
+		c.drawCells(renderer, originX, originY, clip, palette)
 	}
-	c.mtx.RUnlock()
 
-	for i := range c.SubConsoles {
-		c.SubConsoles[i].draw(screen, timeElapsed, offsetX+c.x, offsetY+c.y)
+	for _, sub := range c.subConsoleList() {
+		sub.draw(renderer, timeElapsed, originX, originY, clip)
 	}
 }
 
-func (c *Console) propagateMousePosition(x, y int) {
+func (c *Console) propagateMousePosition(x, y int, inside bool) {
+	viewWidth, viewHeight := c.viewSize()
+
+	c.mtx.Lock()
 	c.mouseX = x - c.x
 	c.mouseY = y - c.y
 
-	if c.mouseX >= c.Width || c.mouseY >= c.Height {
+	if !inside || c.hidden || c.mouseX < 0 || c.mouseY < 0 || c.mouseX >= c.Width || c.mouseY >= c.Height {
 		c.mouseX = -1
 		c.mouseY = -1
-	} else {
-		for i := range c.SubConsoles {
-			c.SubConsoles[i].propagateMousePosition(c.mouseX, c.mouseY)
-		}
+		inside = false
+	}
+
+	c.updateWorldMousePosition(viewWidth, viewHeight)
+	mouseX, mouseY := c.mouseX, c.mouseY
+	subs := append([]*Console(nil), c.SubConsoles...)
+	c.mtx.Unlock()
+
+	for i := range subs {
+		subs[i].propagateMousePosition(mouseX, mouseY, inside)
 	}
 }
 
 func (c *Console) propagateComponentUpdates(timeElapsed float64) {
-	for id := range c.components {
-		if !c.components[id].ShouldDraw() {
-			c.components[id].SetFocus(false)
-		} else if c.components[id].FocusOnClick() && inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
-			x, y := c.components[id].Position()
-			w, h := c.components[id].Size()
-			c.components[id].SetFocus(c.MouseInArea(x, y, w, h))
+	c.beginComponentPass()
+	c.updateFocus()
+
+	components := c.componentList()
+	for i := range components {
+		if components[i].ShouldClose() || !components[i].Update(c, timeElapsed) {
+			c.RemoveComponent(components[i])
 		}
+	}
+	c.endComponentPass()
 
-		if c.components[id].ShouldClose() || !c.components[id].Update(c, timeElapsed) {
-			delete(c.components, id)
+	for _, sub := range c.subConsoleList() {
+		if sub.IsVisible() {
+			sub.propagateComponentUpdates(timeElapsed)
 		}
 	}
+}
+
+// subConsoleList returns a copy of the sub-consoles, so that they can be iterated
+// without holding the lock of the console.
+func (c *Console) subConsoleList() []*Console {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return append([]*Console(nil), c.SubConsoles...)
+}
 
-	for id := range c.SubConsoles {
-		c.SubConsoles[id].propagateComponentUpdates(timeElapsed)
+// floorDiv divides a by b and rounds towards negative infinity.
+func floorDiv(a, b int) int {
+	if a < 0 {
+		return (a - b + 1) / b
 	}
+	return a / b
 }
 
 func (c *Console) elapsedTPS() float64 {
@@ -506,7 +822,7 @@ func (c *Console) update(screen *ebiten.Image) error {
 		}
 	}
 
-	c.draw(screen, timeElapsed, 0, 0)
+	c.Render(NewEbitenRenderer(screen), timeElapsed)
 
 	if c.postRenderHook != nil {
 		if err := c.postRenderHook(screen, timeElapsed); err != nil {
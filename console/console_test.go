@@ -0,0 +1,175 @@
+package console
+
+import (
+	"image"
+	"testing"
+
+	"github.com/BigJk/ramen"
+	"github.com/BigJk/ramen/concolor"
+	"github.com/BigJk/ramen/t"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCellAccessorBounds(test *testing.T) {
+	con, err := New(4, 3, newTestFont(), "")
+	if !assert.NoError(test, err) {
+		return
+	}
+
+	for _, p := range [][2]int{{-1, 0}, {0, -1}, {4, 0}, {0, 3}} {
+		_, err := con.Get(p[0], p[1])
+		assert.Error(test, err, "get %d,%d", p[0], p[1])
+	}
+
+	cases := []struct {
+		name                string
+		x, y, width, height int
+	}{
+		{"NoWidth", 0, 0, 0, 1},
+		{"NoHeight", 0, 0, 1, 0},
+		{"NegativeStart", -1, 0, 2, 2},
+		{"PastRight", 2, 0, 3, 1},
+		{"PastBottom", 0, 1, 1, 3},
+		{"Outside", 4, 3, 1, 1},
+	}
+
+	for _, c := range cases {
+		test.Run(c.name, func(test *testing.T) {
+			region, err := con.Region(c.x, c.y, c.width, c.height)
+			assert.Error(test, err)
+			assert.Nil(test, region)
+		})
+	}
+
+	region, err := con.Region(1, 1, 3, 2)
+	if assert.NoError(test, err) {
+		assert.Len(test, region, 3)
+		assert.Len(test, region[2], 2)
+	}
+}
+
+func TestCellAccessorCopies(test *testing.T) {
+	con, err := New(4, 3, newTestFont(), "")
+	if !assert.NoError(test, err) {
+		return
+	}
+
+	assert.NoError(test, con.Transform(1, 2, t.Char('a')))
+
+	cells := con.Cells()
+	region, err := con.Region(1, 1, 2, 2)
+	if !assert.NoError(test, err) {
+		return
+	}
+
+	assert.Len(test, cells, 4)
+	assert.Len(test, cells[0], 3)
+	assert.Equal(test, 'a', rune(cells[1][2].Char))
+	assert.Equal(test, 'a', rune(region[0][1].Char))
+
+	// Changing the copies doesn't change the console and the other way around.
+	cells[1][2].Char = 'b'
+	region[0][1].Char = 'c'
+	assert.NoError(test, con.Transform(0, 0, t.Char('d')))
+
+	cell, err := con.Get(1, 2)
+	if assert.NoError(test, err) {
+		assert.Equal(test, 'a', rune(cell.Char))
+	}
+	assert.Equal(test, 0, cells[0][0].Char)
+	assert.Equal(test, 'a', rune(con.Cells()[1][2].Char))
+}
+
+func TestEachCell(test *testing.T) {
+	con, err := New(3, 3, newTestFont(), "")
+	if !assert.NoError(test, err) {
+		return
+	}
+
+	assert.NoError(test, con.Transform(2, 0, t.Char('a')))
+	assert.NoError(test, con.Transform(0, 2, t.Char('b')))
+	assert.NoError(test, con.Transform(0, 1, t.Char('c')))
+	assert.NoError(test, con.Transform(1, 1, t.Char('d')))
+
+	var visited []rune
+	con.EachCell(func(x, y int, cell ramen.Cell) bool {
+		visited = append(visited, rune(cell.Char))
+		return true
+	})
+	assert.Equal(test, []rune{'c', 'b', 'd', 'a'}, visited)
+
+	// Returning false stops the iteration.
+	visited = visited[:0]
+	con.EachCell(func(x, y int, cell ramen.Cell) bool {
+		visited = append(visited, rune(cell.Char))
+		return len(visited) < 2
+	})
+	assert.Equal(test, []rune{'c', 'b'}, visited)
+}
+
+func TestScroll(test *testing.T) {
+	fill := ramen.Cell{Char: '.', Foreground: concolor.White}
+
+	cases := []struct {
+		name   string
+		scroll func(con *Console) error
+		rows   []string
+	}{
+		{"Right", func(con *Console) error { return con.Scroll(1, 0, fill) }, []string{".abc", ".efg", ".ijk"}},
+		{"Left", func(con *Console) error { return con.Scroll(-1, 0, fill) }, []string{"bcd.", "fgh.", "jkl."}},
+		{"Down", func(con *Console) error { return con.Scroll(0, 1, fill) }, []string{"....", "abcd", "efgh"}},
+		{"Up", func(con *Console) error { return con.Scroll(0, -1, fill) }, []string{"efgh", "ijkl", "...."}},
+		{"Diagonal", func(con *Console) error { return con.Scroll(2, 1, fill) }, []string{"....", "..ab", "..ef"}},
+		{"None", func(con *Console) error { return con.Scroll(0, 0, fill) }, []string{"abcd", "efgh", "ijkl"}},
+		{"PastSize", func(con *Console) error { return con.Scroll(0, -3, fill) }, []string{"....", "....", "...."}},
+		{"AreaLeft", func(con *Console) error { return con.ScrollArea(1, 0, 3, 2, -1, 0, fill) }, []string{"acd.", "egh.", "ijkl"}},
+		{"AreaDiagonal", func(con *Console) error { return con.ScrollArea(1, 1, 3, 2, 1, -1, fill) }, []string{"abcd", "e.jk", "i..."}},
+		{"AreaPastSize", func(con *Console) error { return con.ScrollArea(0, 0, 2, 2, 5, 0, fill) }, []string{"..cd", "..gh", "ijkl"}},
+	}
+
+	for _, c := range cases {
+		test.Run(c.name, func(test *testing.T) {
+			con, err := New(4, 3, newTestFont(), "")
+			if !assert.NoError(test, err) {
+				return
+			}
+
+			con.Print(0, 0, "abcd\nefgh\nijkl")
+			assert.NoError(test, c.scroll(con))
+			assert.Equal(test, c.rows, charRows(test, con))
+		})
+	}
+}
+
+func TestScrollAreaBounds(test *testing.T) {
+	con, err := New(4, 3, newTestFont(), "")
+	if !assert.NoError(test, err) {
+		return
+	}
+
+	con.Print(0, 0, "abcd\nefgh\nijkl")
+
+	assert.Error(test, con.ScrollArea(-1, 0, 2, 2, 1, 0, emptyCell))
+	assert.Error(test, con.ScrollArea(0, -1, 2, 2, 1, 0, emptyCell))
+	assert.Error(test, con.ScrollArea(3, 0, 2, 1, 1, 0, emptyCell))
+	assert.Error(test, con.ScrollArea(0, 2, 1, 2, 1, 0, emptyCell))
+	assert.NoError(test, con.ScrollArea(0, 0, 0, 2, 1, 0, emptyCell))
+
+	// Failed scrolls don't change the console.
+	assert.Equal(test, []string{"abcd", "efgh", "ijkl"}, charRows(test, con))
+}
+
+func TestScrollMarksDirty(test *testing.T) {
+	con, err := New(4, 2, newTestFont(), "")
+	if !assert.NoError(test, err) {
+		return
+	}
+
+	con.Print(0, 0, "aabc")
+	dirtyCells(con)
+
+	// Only the cells that changed need to be drawn again.
+	assert.NoError(test, con.Scroll(1, 0, ramen.Cell{Char: '.', Foreground: concolor.White}))
+	assert.Equal(test, []string{".aab", ".   "}, charRows(test, con))
+	assert.Equal(test, []image.Point{{0, 0}, {0, 1}, {2, 0}, {3, 0}}, dirtyCells(con))
+}
@@ -0,0 +1,327 @@
+package console
+
+import (
+	"image"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// EventType represents the kind of an input event.
+type EventType int
+
+const (
+	// EventKeyDown is emitted when a key was pressed.
+	EventKeyDown = EventType(iota)
+	// EventKeyUp is emitted when a key was released.
+	EventKeyUp
+	// EventKeyRepeat is emitted repeatedly while a key is held down.
+	EventKeyRepeat
+	// EventTextInput is emitted for every char that was typed.
+	EventTextInput
+	// EventMouseMove is emitted when the mouse moved to another cell while no button is pressed.
+	EventMouseMove
+	// EventMousePress is emitted when a mouse button was pressed.
+	EventMousePress
+	// EventMouseRelease is emitted when a mouse button was released.
+	EventMouseRelease
+	// EventMouseWheel is emitted when the mouse wheel was scrolled.
+	EventMouseWheel
+	// EventMouseDrag is emitted when the mouse moved to another cell while a button is pressed.
+	EventMouseDrag
+	// EventFocusChange is emitted when the focused component of a console changed. It is
+	// passed to the blurred and the focused component and the event hook of the console.
+	EventFocusChange
+)
+
+const (
+	keyRepeatDelay    = 30
+	keyRepeatInterval = 3
+)
+
+var mouseButtons = []ebiten.MouseButton{ebiten.MouseButtonLeft, ebiten.MouseButtonRight, ebiten.MouseButtonMiddle}
+
+// Event represents a single keyboard or mouse input.
+type Event struct {
+	Type EventType
+
+	// Key is the key of key events.
+	Key ebiten.Key
+	// Char is the typed char of text input events.
+	Char rune
+	// Button is the mouse button of press, release and drag events.
+	Button ebiten.MouseButton
+
+	// MouseX and MouseY are the cell of the mouse relative to the console that
+	// currently receives the event.
+	MouseX int
+	MouseY int
+	// DeltaX and DeltaY are the amount of cells the mouse moved for move and drag events.
+	DeltaX int
+	DeltaY int
+	// WheelX and WheelY are the scroll offsets of wheel events.
+	WheelX float64
+	WheelY float64
+
+	// Shift, Control and Alt are true if the modifier keys were held down
+	// during key and mouse events.
+	Shift   bool
+	Control bool
+	Alt     bool
+
+	// Focused and Blurred are the components that gained and lost the focus
+	// in focus change events. Both can be nil.
+	Focused Component
+	Blurred Component
+
+	// Handled stops the propagation of the event if set to true.
+	Handled bool
+
+	rootX int
+	rootY int
+
+	// target is the console that receives the event directly without propagation.
+	target *Console
+}
+
+// IsMouse returns true if the event is a mouse event.
+func (e *Event) IsMouse() bool {
+	return e.Type >= EventMouseMove && e.Type <= EventMouseDrag
+}
+
+// EventHandler can be implemented by components that want to receive input events.
+// Mouse events are passed to the components under the mouse and all other events
+// to the focused components. Set Handled on the event to stop its propagation.
+type EventHandler interface {
+	HandleEvent(con *Console, event *Event)
+}
+
+// eventQueue collects the events of the main console until they are dispatched.
+type eventQueue struct {
+	mtx    sync.Mutex
+	events []Event
+
+	mouseX  int
+	mouseY  int
+	hasLast bool
+}
+
+// SetEventHook will apply a hook that gets triggered for every event that reaches the
+// console and wasn't handled by a sub-console or component of it. In contrast to
+// the other hooks this can also be used on sub-consoles.
+func (c *Console) SetEventHook(hook func(event *Event) error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.eventHook = hook
+}
+
+// PushEvent queues an event that will be dispatched on the next update together
+// with the keyboard and mouse events. The mouse position of the event is relative
+// to the console it was pushed to.
+func (c *Console) PushEvent(event Event) {
+	event.rootX, event.rootY = event.MouseX, event.MouseY
+
+	root := c
+	for root.parent != nil {
+		x, y := root.Position()
+		event.rootX += x
+		event.rootY += y
+		root = root.parent
+	}
+
+	root.queue.mtx.Lock()
+	root.queue.events = append(root.queue.events, event)
+	root.queue.mtx.Unlock()
+}
+
+// collectEvents queues the keyboard and mouse events of this tick. The mouse
+// position is given in cells of the main console.
+func (c *Console) collectEvents(input Input, mouseX, mouseY int) {
+	var events []Event
+
+	for key := ebiten.Key(0); key <= ebiten.KeyMax; key++ {
+		switch d := input.KeyPressDuration(key); {
+		case input.IsKeyJustPressed(key):
+			events = append(events, Event{Type: EventKeyDown, Key: key})
+		case input.IsKeyJustReleased(key):
+			events = append(events, Event{Type: EventKeyUp, Key: key})
+		case d >= keyRepeatDelay && (d-keyRepeatDelay)%keyRepeatInterval == 0:
+			events = append(events, Event{Type: EventKeyRepeat, Key: key})
+		}
+	}
+
+	for _, char := range input.InputChars() {
+		events = append(events, Event{Type: EventTextInput, Char: char})
+	}
+
+	mouse := Event{rootX: mouseX, rootY: mouseY}
+	if c.queue.hasLast && (mouseX != c.queue.mouseX || mouseY != c.queue.mouseY) {
+		move := mouse
+		move.Type = EventMouseMove
+		move.DeltaX, move.DeltaY = mouseX-c.queue.mouseX, mouseY-c.queue.mouseY
+
+		for _, button := range mouseButtons {
+			if input.IsMouseButtonPressed(button) && !input.IsMouseButtonJustPressed(button) {
+				move.Type = EventMouseDrag
+				move.Button = button
+				break
+			}
+		}
+
+		events = append(events, move)
+	}
+	c.queue.mouseX, c.queue.mouseY, c.queue.hasLast = mouseX, mouseY, true
+
+	for _, button := range mouseButtons {
+		if input.IsMouseButtonJustPressed(button) {
+			press := mouse
+			press.Type = EventMousePress
+			press.Button = button
+			events = append(events, press)
+		} else if input.IsMouseButtonJustReleased(button) {
+			release := mouse
+			release.Type = EventMouseRelease
+			release.Button = button
+			events = append(events, release)
+		}
+	}
+
+	if wx, wy := input.Wheel(); wx != 0 || wy != 0 {
+		wheel := mouse
+		wheel.Type = EventMouseWheel
+		wheel.WheelX, wheel.WheelY = wx, wy
+		events = append(events, wheel)
+	}
+
+	shift := input.IsKeyPressed(ebiten.KeyShift) || input.IsKeyPressed(ebiten.KeyShiftLeft) || input.IsKeyPressed(ebiten.KeyShiftRight)
+	control := input.IsKeyPressed(ebiten.KeyControl) || input.IsKeyPressed(ebiten.KeyControlLeft) || input.IsKeyPressed(ebiten.KeyControlRight)
+	alt := input.IsKeyPressed(ebiten.KeyAlt) || input.IsKeyPressed(ebiten.KeyAltLeft) || input.IsKeyPressed(ebiten.KeyAltRight)
+	for i := range events {
+		events[i].Shift, events[i].Control, events[i].Alt = shift, control, alt
+	}
+
+	c.queue.mtx.Lock()
+	c.queue.events = append(c.queue.events, events...)
+	c.queue.mtx.Unlock()
+}
+
+// dispatchEvents dispatches all queued events and clears the queue.
+func (c *Console) dispatchEvents() error {
+	c.queue.mtx.Lock()
+	events := c.queue.events
+	c.queue.events = nil
+	c.queue.mtx.Unlock()
+
+	for i := range events {
+		var err error
+		if events[i].target != nil {
+			err = events[i].target.dispatchFocusEvent(&events[i])
+		} else {
+			c.mtx.RLock()
+			clip := image.Rect(0, 0, c.Width, c.Height)
+			c.mtx.RUnlock()
+
+			err = c.dispatchEvent(&events[i], 0, 0, clip)
+			if err == nil && !events[i].Handled && !events[i].IsMouse() {
+				if target := c.focusKeyTarget(); target != nil {
+					target.handleFocusKey(&events[i])
+				}
+			}
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dispatchEvent passes the event to the topmost visible sub-consoles first and lets it
+// bubble up to this console, its components and its event hook until it is handled.
+// Focus navigation keys that weren't handled are passed to focusKeyTarget afterwards.
+// Mouse events only reach the topmost sub-console under the mouse. The origin is the
+// absolute position of the console in cells of the main console and clip is the visible
+// part of the console in the same coordinates.
+func (c *Console) dispatchEvent(event *Event, originX, originY int, clip image.Rectangle) error {
+	c.mtx.RLock()
+	subs := append([]*Console(nil), c.SubConsoles...)
+	hook := c.eventHook
+	c.mtx.RUnlock()
+
+	click := event.Type == EventMousePress && event.Button == ebiten.MouseButtonLeft
+	covered := false
+
+	for i := len(subs) - 1; i >= 0; i-- {
+		subs[i].mtx.RLock()
+		x, y := originX+subs[i].x, originY+subs[i].y
+		subClip := clip.Intersect(image.Rect(x, y, x+subs[i].Width, y+subs[i].Height))
+		hidden := subs[i].hidden
+		subs[i].mtx.RUnlock()
+
+		if hidden {
+			continue
+		}
+
+		// Like drawing, hit-testing is clipped by the parents of the sub-console.
+		if event.IsMouse() && !image.Pt(event.rootX, event.rootY).In(subClip) {
+			continue
+		}
+
+		// A click on a sub-console can't focus the components below it.
+		if click {
+			covered = true
+			c.blurOnClick()
+		}
+
+		if err := subs[i].dispatchEvent(event, x, y, subClip); err != nil || event.Handled {
+			return err
+		}
+
+		if event.IsMouse() {
+			break
+		}
+	}
+
+	event.MouseX, event.MouseY = event.rootX-originX, event.rootY-originY
+
+	if click && !covered {
+		c.clickFocus(event.MouseX, event.MouseY)
+	}
+
+	c.beginComponentPass()
+	defer c.endComponentPass()
+
+	components := c.componentList()
+
+	// Mouse events are passed to the components under the mouse from the topmost
+	// to the bottommost one.
+	for i := len(components) - 1; i >= 0; i-- {
+		handler, ok := components[i].(EventHandler)
+		if !ok || !components[i].ShouldDraw() {
+			continue
+		}
+
+		if event.IsMouse() {
+			x, y := components[i].Position()
+			w, h := components[i].Size()
+			if event.MouseX < x || event.MouseY < y || event.MouseX >= x+w || event.MouseY >= y+h {
+				continue
+			}
+		} else if !components[i].IsFocused() {
+			continue
+		}
+
+		handler.HandleEvent(c, event)
+		if event.Handled {
+			return nil
+		}
+	}
+
+	if hook != nil {
+		return hook(event)
+	}
+
+	return nil
+}
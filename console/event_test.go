@@ -0,0 +1,65 @@
+package console
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMouseEventClipping(test *testing.T) {
+	con, err := New(10, 10, newTestFont(), "")
+	if !assert.NoError(test, err) {
+		return
+	}
+
+	corner, err := con.CreateSubConsole(0, 0, 4, 4)
+	if !assert.NoError(test, err) {
+		return
+	}
+	assert.NoError(test, corner.SetPosition(8, 8))
+
+	panel, err := con.CreateSubConsole(2, 2, 4, 4)
+	if !assert.NoError(test, err) {
+		return
+	}
+
+	nested, err := panel.CreateSubConsole(0, 0, 2, 2)
+	if !assert.NoError(test, err) {
+		return
+	}
+	assert.NoError(test, nested.SetPosition(3, 3))
+
+	var hits []string
+	record := func(name string) func(event *Event) error {
+		return func(event *Event) error {
+			hits = append(hits, name)
+			event.Handled = true
+			return nil
+		}
+	}
+	con.SetEventHook(record("root"))
+	corner.SetEventHook(record("corner"))
+	panel.SetEventHook(record("panel"))
+	nested.SetEventHook(record("nested"))
+
+	cases := []struct {
+		x, y   int
+		target string
+	}{
+		{9, 9, "corner"},
+		// Outside of the main console the sub-console is clipped.
+		{11, 11, "root"},
+		{5, 5, "nested"},
+		{4, 4, "panel"},
+		// Outside of its parent the nested console is clipped.
+		{6, 6, "root"},
+	}
+
+	for _, c := range cases {
+		hits = nil
+		con.PushEvent(Event{Type: EventMousePress, Button: ebiten.MouseButtonLeft, MouseX: c.x, MouseY: c.y})
+		assert.NoError(test, con.dispatchEvents())
+		assert.Equal(test, []string{c.target}, hits, "click at %d,%d", c.x, c.y)
+	}
+}
@@ -0,0 +1,410 @@
+package console
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/BigJk/ramen/t"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Focusable can be implemented by components to decide if they can receive the
+// keyboard focus. Components that don't implement it can be focused while visible.
+type Focusable interface {
+	CanFocus() bool
+}
+
+// focusState tracks the focused component of a console.
+type focusState struct {
+	mtx      sync.Mutex
+	focused  Component
+	tabOrder []Component
+
+	// active is the console that focused a component last. It is only
+	// tracked on the main console.
+	active *Console
+}
+
+// Focus moves the keyboard focus of the console to the component. At most one
+// component of a console is focused at a time. Passing nil removes the focus.
+// Components that are added in the same callback can already be focused.
+func (c *Console) Focus(component Component) error {
+	if component != nil {
+		c.compMtx.Lock()
+		mounted := c.mountedComponent(component.ID()) == component
+		c.compMtx.Unlock()
+
+		if !mounted {
+			return fmt.Errorf("component is not mounted to the console")
+		} else if !canFocus(component) {
+			return fmt.Errorf("component can't be focused")
+		}
+	}
+
+	c.changeFocus(component)
+	return nil
+}
+
+// Focused returns the focused component of the console or nil if no component is focused.
+func (c *Console) Focused() Component {
+	c.focus.mtx.Lock()
+	defer c.focus.mtx.Unlock()
+
+	return c.focus.focused
+}
+
+// FocusNext moves the focus to the next component in the tab order.
+func (c *Console) FocusNext() {
+	c.cycleFocus(1)
+}
+
+// FocusPrevious moves the focus to the previous component in the tab order.
+func (c *Console) FocusPrevious() {
+	c.cycleFocus(-1)
+}
+
+// SetTabOrder sets the order in which Tab moves the focus through the components.
+// Components that are not part of the order follow after them, sorted from top to
+// bottom and left to right, which is also the default order.
+func (c *Console) SetTabOrder(components ...Component) {
+	c.focus.mtx.Lock()
+	defer c.focus.mtx.Unlock()
+
+	c.focus.tabOrder = append([]Component(nil), components...)
+}
+
+// DrawFocusRing draws a frame around the focused component of the console. As the
+// frame is drawn outside of the component it should be drawn every frame after the
+// console was cleared.
+func (c *Console) DrawFocusRing(style FrameStyle, transformer ...t.Transformer) error {
+	focused := c.Focused()
+	if focused == nil {
+		return nil
+	}
+
+	x, y := focused.Position()
+	w, h := focused.Size()
+	return c.DrawFrame(x-1, y-1, w+2, h+2, style, "", transformer...)
+}
+
+// tabOrder returns the focusable components in tab order.
+func (c *Console) tabOrder() []Component {
+	c.focus.mtx.Lock()
+	explicit := c.focus.tabOrder
+	c.focus.mtx.Unlock()
+
+	components := c.componentList()
+	mounted := map[Component]bool{}
+	for _, comp := range components {
+		mounted[comp] = true
+	}
+
+	var order, rest []Component
+	listed := map[Component]bool{}
+	for _, comp := range explicit {
+		if mounted[comp] && canFocus(comp) && !listed[comp] {
+			order = append(order, comp)
+			listed[comp] = true
+		}
+	}
+
+	for _, comp := range components {
+		if !listed[comp] && canFocus(comp) {
+			rest = append(rest, comp)
+		}
+	}
+
+	sort.Slice(rest, func(i, j int) bool {
+		xi, yi := rest[i].Position()
+		xj, yj := rest[j].Position()
+		if yi != yj {
+			return yi < yj
+		} else if xi != xj {
+			return xi < xj
+		}
+		return rest[i].ID() < rest[j].ID()
+	})
+
+	return append(order, rest...)
+}
+
+// cycleFocus moves the focus by dir steps through the tab order.
+func (c *Console) cycleFocus(dir int) {
+	order := c.tabOrder()
+	if len(order) == 0 {
+		return
+	}
+
+	focused := c.Focused()
+	index := -1
+	for i := range order {
+		if order[i] == focused {
+			index = i
+			break
+		}
+	}
+
+	switch {
+	case index < 0 && dir > 0:
+		index = 0
+	case index < 0:
+		index = len(order) - 1
+	default:
+		index = (index + dir + len(order)) % len(order)
+	}
+
+	c.changeFocus(order[index])
+}
+
+// moveFocus moves the focus to the nearest component in the direction of dx, dy.
+func (c *Console) moveFocus(dx, dy int) {
+	focused := c.Focused()
+	if focused == nil {
+		return
+	}
+
+	fx, fy := componentCenter(focused)
+
+	var best Component
+	bestScore := math.Inf(1)
+	for _, comp := range c.tabOrder() {
+		if comp == focused {
+			continue
+		}
+
+		cx, cy := componentCenter(comp)
+		primary, secondary := (cx-fx)*float64(dx)+(cy-fy)*float64(dy), math.Abs((cx-fx)*float64(dy))+math.Abs((cy-fy)*float64(dx))
+		if primary <= 0 {
+			continue
+		}
+
+		// Components that are in line with the focused one are preferred.
+		if score := primary + secondary*2; score < bestScore {
+			best, bestScore = comp, score
+		}
+	}
+
+	if best != nil {
+		c.changeFocus(best)
+	}
+}
+
+// focusKeyTarget returns the console that handles the focus navigation keys. This is
+// the console that focused a component last, so Tab moves through the tab order of
+// that console. If that console lost its focus or isn't shown anymore, the topmost
+// visible console with a focused component and after that the topmost visible console
+// with focusable components is used. It needs to be called on the main console.
+func (c *Console) focusKeyTarget() *Console {
+	c.focus.mtx.Lock()
+	active := c.focus.active
+	c.focus.mtx.Unlock()
+
+	if active != nil && active.Focused() != nil && active.isShown() {
+		return active
+	}
+
+	var fallback *Console
+
+	var find func(con *Console) *Console
+	find = func(con *Console) *Console {
+		subs := con.subConsoleList()
+		for i := len(subs) - 1; i >= 0; i-- {
+			if !subs[i].IsVisible() {
+				continue
+			}
+			if target := find(subs[i]); target != nil {
+				return target
+			}
+		}
+
+		if con.Focused() != nil {
+			return con
+		}
+		if fallback == nil && len(con.tabOrder()) > 0 {
+			fallback = con
+		}
+		return nil
+	}
+
+	if target := find(c); target != nil {
+		return target
+	}
+	return fallback
+}
+
+// isShown returns true if the console and all of its parents are visible and the
+// sub-consoles are still attached to their parents.
+func (c *Console) isShown() bool {
+	for con := c; con.parent != nil; con = con.parent {
+		if !con.IsVisible() {
+			return false
+		}
+
+		attached := false
+		for _, sub := range con.parent.subConsoleList() {
+			attached = attached || sub == con
+		}
+		if !attached {
+			return false
+		}
+	}
+	return true
+}
+
+// handleFocusKey moves the focus on Tab, Shift-Tab and the arrow keys. Arrow
+// keys only move the focus if a component is already focused.
+func (c *Console) handleFocusKey(event *Event) {
+	if event.Type != EventKeyDown && event.Type != EventKeyRepeat {
+		return
+	}
+
+	focused := c.Focused() != nil
+	switch {
+	case event.Key == ebiten.KeyTab && event.Shift:
+		c.cycleFocus(-1)
+	case event.Key == ebiten.KeyTab:
+		c.cycleFocus(1)
+	case event.Key == ebiten.KeyArrowUp && focused:
+		c.moveFocus(0, -1)
+	case event.Key == ebiten.KeyArrowDown && focused:
+		c.moveFocus(0, 1)
+	case event.Key == ebiten.KeyArrowLeft && focused:
+		c.moveFocus(-1, 0)
+	case event.Key == ebiten.KeyArrowRight && focused:
+		c.moveFocus(1, 0)
+	default:
+		return
+	}
+
+	if len(c.tabOrder()) > 0 {
+		event.Handled = true
+	}
+}
+
+// updateFocus keeps the focus in sync with the components.
+func (c *Console) updateFocus() {
+	focused := c.Focused()
+
+	// Drop the focus of components that were removed, hidden or unfocused and adopt
+	// components that were focused with SetFocus directly.
+	components := c.componentList()
+	if focused != nil {
+		mounted := false
+		for _, comp := range components {
+			mounted = mounted || comp == focused
+		}
+
+		if !mounted || !canFocus(focused) || !focused.IsFocused() {
+			focused = nil
+			c.changeFocus(nil)
+		}
+	}
+
+	for _, comp := range components {
+		if comp != focused && comp.IsFocused() {
+			if focused == nil && canFocus(comp) {
+				c.changeFocus(comp)
+				return
+			}
+			comp.SetFocus(false)
+		}
+	}
+}
+
+// clickFocus focuses the FocusOnClick component at the position or removes the
+// focus of a focused FocusOnClick component if there is none.
+func (c *Console) clickFocus(x, y int) {
+	// Only the topmost component under the mouse can be clicked.
+	if target := c.ComponentAt(x, y); target != nil && target.FocusOnClick() && canFocus(target) {
+		c.changeFocus(target)
+	} else {
+		c.blurOnClick()
+	}
+}
+
+// blurOnClick removes the focus of a focused FocusOnClick component.
+func (c *Console) blurOnClick() {
+	if focused := c.Focused(); focused != nil && focused.FocusOnClick() {
+		c.changeFocus(nil)
+	}
+}
+
+// changeFocus focuses the component, removes the focus from all other components and
+// queues a focus change event.
+func (c *Console) changeFocus(component Component) {
+	c.focus.mtx.Lock()
+	previous := c.focus.focused
+	c.focus.focused = component
+	c.focus.mtx.Unlock()
+
+	if previous != nil {
+		previous.SetFocus(false)
+	}
+	for _, comp := range c.componentList() {
+		comp.SetFocus(comp == component)
+	}
+	if component != nil {
+		component.SetFocus(true)
+	}
+
+	root := c
+	for root.parent != nil {
+		root = root.parent
+	}
+
+	root.focus.mtx.Lock()
+	if component != nil {
+		root.focus.active = c
+	} else if root.focus.active == c {
+		root.focus.active = nil
+	}
+	root.focus.mtx.Unlock()
+
+	if previous == component {
+		return
+	}
+
+	root.queue.mtx.Lock()
+	root.queue.events = append(root.queue.events, Event{Type: EventFocusChange, Focused: component, Blurred: previous, target: c})
+	root.queue.mtx.Unlock()
+}
+
+// dispatchFocusEvent passes a focus change event to the blurred and the focused
+// component and the event hook of the console.
+func (c *Console) dispatchFocusEvent(event *Event) error {
+	for _, comp := range []Component{event.Blurred, event.Focused} {
+		if handler, ok := comp.(EventHandler); ok {
+			handler.HandleEvent(c, event)
+			if event.Handled {
+				return nil
+			}
+		}
+	}
+
+	c.mtx.RLock()
+	hook := c.eventHook
+	c.mtx.RUnlock()
+
+	if hook != nil {
+		return hook(event)
+	}
+	return nil
+}
+
+func canFocus(comp Component) bool {
+	if !comp.ShouldDraw() {
+		return false
+	}
+	if f, ok := comp.(Focusable); ok {
+		return f.CanFocus()
+	}
+	return true
+}
+
+func componentCenter(comp Component) (float64, float64) {
+	x, y := comp.Position()
+	w, h := comp.Size()
+	return float64(x) + float64(w)/2, float64(y) + float64(h)/2
+}
@@ -0,0 +1,100 @@
+package console
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFocusKeyRouting(test *testing.T) {
+	con, err := New(10, 5, newTestFont(), "")
+	if !assert.NoError(test, err) {
+		return
+	}
+
+	sub, err := con.CreateSubConsole(5, 0, 5, 5)
+	if !assert.NoError(test, err) {
+		return
+	}
+
+	a := &testComponent{ComponentBase: NewComponentBase(0, 0, 2, 1)}
+	b := &testComponent{ComponentBase: NewComponentBase(0, 2, 2, 1)}
+	s := &testComponent{ComponentBase: NewComponentBase(0, 0, 2, 1)}
+	con.AddComponent(a)
+	con.AddComponent(b)
+	sub.AddComponent(s)
+
+	press := func(shift bool) {
+		con.PushEvent(Event{Type: EventKeyDown, Key: ebiten.KeyTab, Shift: shift})
+		assert.NoError(test, con.dispatchEvents())
+	}
+
+	// Without any focus Tab starts in the topmost console with focusable components.
+	press(false)
+	assert.Equal(test, s, sub.Focused())
+	assert.Nil(test, con.Focused())
+
+	// Tab moves through the tab order of the console that owns the focus.
+	assert.NoError(test, con.Focus(a))
+	press(false)
+	assert.Equal(test, b, con.Focused())
+	press(false)
+	assert.Equal(test, a, con.Focused())
+	press(true)
+	assert.Equal(test, b, con.Focused())
+	assert.Equal(test, s, sub.Focused())
+
+	assert.NoError(test, sub.Focus(s))
+	press(false)
+	assert.Equal(test, s, sub.Focused())
+	assert.Equal(test, b, con.Focused())
+
+	// Hidden consoles don't receive focus keys.
+	assert.NoError(test, sub.SetVisible(false))
+	press(false)
+	assert.Equal(test, a, con.Focused())
+
+	// Handled key events don't move the focus.
+	con.SetEventHook(func(event *Event) error {
+		event.Handled = event.Key == ebiten.KeyTab
+		return nil
+	})
+	press(false)
+	assert.Equal(test, a, con.Focused())
+}
+
+func TestFocusStaleComponent(test *testing.T) {
+	con, err := New(10, 5, newTestFont(), "")
+	if !assert.NoError(test, err) {
+		return
+	}
+
+	mounted := &testComponent{ComponentBase: NewComponentBase(0, 0, 2, 1)}
+	stale := &testComponent{ComponentBase: &ComponentBase{Width: 2, Height: 1, id: mounted.ID(), show: true}}
+	con.AddComponent(mounted)
+
+	// The stale component shares the id of the mounted one.
+	assert.Equal(test, mounted.ID(), stale.ID())
+	assert.Error(test, con.Focus(stale))
+	assert.False(test, stale.IsFocused())
+	assert.Nil(test, con.Focused())
+
+	dialog := &testComponent{ComponentBase: NewComponentBase(0, 2, 2, 1)}
+	staleDialog := &testComponent{ComponentBase: &ComponentBase{Y: 2, Width: 2, Height: 1, id: dialog.ID(), show: true}}
+
+	opener := &testComponent{ComponentBase: NewComponentBase(4, 0, 2, 1)}
+	opener.onUpdate = func(con *Console) {
+		opener.onUpdate = nil
+		con.AddComponent(dialog)
+
+		// Pending components are compared with the queued component.
+		assert.Error(test, con.Focus(staleDialog))
+		assert.NoError(test, con.Focus(dialog))
+	}
+	con.AddComponent(opener)
+	con.propagateComponentUpdates(0)
+
+	assert.Equal(test, dialog, con.Focused())
+	assert.False(test, staleDialog.IsFocused())
+}
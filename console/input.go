@@ -0,0 +1,304 @@
+package console
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Input represents the source of the keyboard and mouse state. The main console owns
+// the input and passes it down to its sub-consoles and components, so that they
+// never have to poll ebiten directly. This makes it possible to drive a console with
+// a fake input source in tests.
+type Input interface {
+	// Update is called by the main console once per tick before the state is read.
+	Update()
+
+	// CursorPosition returns the position of the mouse cursor in pixels.
+	CursorPosition() (int, int)
+	// Wheel returns the scroll offsets of the mouse wheel in this tick.
+	Wheel() (float64, float64)
+	IsMouseButtonPressed(button ebiten.MouseButton) bool
+	IsMouseButtonJustPressed(button ebiten.MouseButton) bool
+	IsMouseButtonJustReleased(button ebiten.MouseButton) bool
+
+	IsKeyPressed(key ebiten.Key) bool
+	IsKeyJustPressed(key ebiten.Key) bool
+	IsKeyJustReleased(key ebiten.Key) bool
+	// KeyPressDuration returns the amount of ticks the key is held down.
+	KeyPressDuration(key ebiten.Key) int
+
+	// InputChars returns the chars that were typed in this tick.
+	InputChars() []rune
+}
+
+// SetInput replaces the input source of the console. This can only be used on the main console.
+func (c *Console) SetInput(input Input) error {
+	if c.isSubConsole {
+		return fmt.Errorf("can't change input of sub-console")
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.input = input
+	return nil
+}
+
+// Input returns the input source of the main console.
+func (c *Console) Input() Input {
+	root := c
+	for root.parent != nil {
+		root = root.parent
+	}
+
+	root.mtx.RLock()
+	defer root.mtx.RUnlock()
+
+	return root.input
+}
+
+// EbitenInput is the input source that reads the keyboard and mouse state from ebiten.
+type EbitenInput struct{}
+
+// Update does nothing as ebiten updates the state itself.
+func (EbitenInput) Update() {}
+
+// CursorPosition returns the position of the mouse cursor in pixels.
+func (EbitenInput) CursorPosition() (int, int) { return ebiten.CursorPosition() }
+
+// Wheel returns the scroll offsets of the mouse wheel in this tick.
+func (EbitenInput) Wheel() (float64, float64) { return ebiten.Wheel() }
+
+// IsMouseButtonPressed returns true if the mouse button is held down.
+func (EbitenInput) IsMouseButtonPressed(button ebiten.MouseButton) bool {
+	return ebiten.IsMouseButtonPressed(button)
+}
+
+// IsMouseButtonJustPressed returns true if the mouse button was pressed in this tick.
+func (EbitenInput) IsMouseButtonJustPressed(button ebiten.MouseButton) bool {
+	return inpututil.IsMouseButtonJustPressed(button)
+}
+
+// IsMouseButtonJustReleased returns true if the mouse button was released in this tick.
+func (EbitenInput) IsMouseButtonJustReleased(button ebiten.MouseButton) bool {
+	return inpututil.IsMouseButtonJustReleased(button)
+}
+
+// IsKeyPressed returns true if the key is held down.
+func (EbitenInput) IsKeyPressed(key ebiten.Key) bool { return ebiten.IsKeyPressed(key) }
+
+// IsKeyJustPressed returns true if the key was pressed in this tick.
+func (EbitenInput) IsKeyJustPressed(key ebiten.Key) bool { return inpututil.IsKeyJustPressed(key) }
+
+// IsKeyJustReleased returns true if the key was released in this tick.
+func (EbitenInput) IsKeyJustReleased(key ebiten.Key) bool { return inpututil.IsKeyJustReleased(key) }
+
+// KeyPressDuration returns the amount of ticks the key is held down.
+func (EbitenInput) KeyPressDuration(key ebiten.Key) int { return inpututil.KeyPressDuration(key) }
+
+// InputChars returns the chars that were typed in this tick.
+func (EbitenInput) InputChars() []rune { return ebiten.InputChars() }
+
+// MemoryInput is a scriptable input source that is kept in memory. Changes to the
+// state become visible with the next update of the console, so to simulate a click
+// press the button, update, release the button and update again.
+type MemoryInput struct {
+	mtx sync.Mutex
+
+	cursorX int
+	cursorY int
+
+	keys        map[ebiten.Key]bool
+	keyDuration map[ebiten.Key]int
+	keyReleased map[ebiten.Key]bool
+
+	buttons        map[ebiten.MouseButton]bool
+	buttonDuration map[ebiten.MouseButton]int
+	buttonReleased map[ebiten.MouseButton]bool
+
+	pendingChars []rune
+	chars        []rune
+
+	pendingWheelX float64
+	pendingWheelY float64
+	wheelX        float64
+	wheelY        float64
+}
+
+// NewMemoryInput creates a new input source without any pressed keys or buttons.
+func NewMemoryInput() *MemoryInput {
+	return &MemoryInput{
+		keys:           map[ebiten.Key]bool{},
+		keyDuration:    map[ebiten.Key]int{},
+		keyReleased:    map[ebiten.Key]bool{},
+		buttons:        map[ebiten.MouseButton]bool{},
+		buttonDuration: map[ebiten.MouseButton]int{},
+		buttonReleased: map[ebiten.MouseButton]bool{},
+	}
+}
+
+// SetCursor moves the mouse cursor to the given position in pixels.
+func (m *MemoryInput) SetCursor(x, y int) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.cursorX, m.cursorY = x, y
+}
+
+// PressKey holds the key down until it is released.
+func (m *MemoryInput) PressKey(key ebiten.Key) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.keys[key] = true
+}
+
+// ReleaseKey releases the key.
+func (m *MemoryInput) ReleaseKey(key ebiten.Key) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	delete(m.keys, key)
+}
+
+// PressButton holds the mouse button down until it is released.
+func (m *MemoryInput) PressButton(button ebiten.MouseButton) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.buttons[button] = true
+}
+
+// ReleaseButton releases the mouse button.
+func (m *MemoryInput) ReleaseButton(button ebiten.MouseButton) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	delete(m.buttons, button)
+}
+
+// TypeText adds the chars of the text to the typed chars of the next tick.
+func (m *MemoryInput) TypeText(text string) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.pendingChars = append(m.pendingChars, []rune(text)...)
+}
+
+// ScrollWheel adds the offsets to the mouse wheel of the next tick.
+func (m *MemoryInput) ScrollWheel(x, y float64) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.pendingWheelX += x
+	m.pendingWheelY += y
+}
+
+// Update applies all changes since the last update.
+func (m *MemoryInput) Update() {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.keyReleased = map[ebiten.Key]bool{}
+	for key := range m.keyDuration {
+		if !m.keys[key] {
+			m.keyReleased[key] = true
+			delete(m.keyDuration, key)
+		}
+	}
+	for key := range m.keys {
+		m.keyDuration[key]++
+	}
+
+	m.buttonReleased = map[ebiten.MouseButton]bool{}
+	for button := range m.buttonDuration {
+		if !m.buttons[button] {
+			m.buttonReleased[button] = true
+			delete(m.buttonDuration, button)
+		}
+	}
+	for button := range m.buttons {
+		m.buttonDuration[button]++
+	}
+
+	m.chars, m.pendingChars = m.pendingChars, nil
+	m.wheelX, m.wheelY = m.pendingWheelX, m.pendingWheelY
+	m.pendingWheelX, m.pendingWheelY = 0, 0
+}
+
+// CursorPosition returns the position of the mouse cursor in pixels.
+func (m *MemoryInput) CursorPosition() (int, int) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	return m.cursorX, m.cursorY
+}
+
+// Wheel returns the scroll offsets of the mouse wheel in this tick.
+func (m *MemoryInput) Wheel() (float64, float64) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	return m.wheelX, m.wheelY
+}
+
+// IsMouseButtonPressed returns true if the mouse button is held down.
+func (m *MemoryInput) IsMouseButtonPressed(button ebiten.MouseButton) bool {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	return m.buttonDuration[button] > 0
+}
+
+// IsMouseButtonJustPressed returns true if the mouse button was pressed in this tick.
+func (m *MemoryInput) IsMouseButtonJustPressed(button ebiten.MouseButton) bool {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	return m.buttonDuration[button] == 1
+}
+
+// IsMouseButtonJustReleased returns true if the mouse button was released in this tick.
+func (m *MemoryInput) IsMouseButtonJustReleased(button ebiten.MouseButton) bool {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	return m.buttonReleased[button]
+}
+
+// IsKeyPressed returns true if the key is held down.
+func (m *MemoryInput) IsKeyPressed(key ebiten.Key) bool {
+	return m.KeyPressDuration(key) > 0
+}
+
+// IsKeyJustPressed returns true if the key was pressed in this tick.
+func (m *MemoryInput) IsKeyJustPressed(key ebiten.Key) bool {
+	return m.KeyPressDuration(key) == 1
+}
+
+// IsKeyJustReleased returns true if the key was released in this tick.
+func (m *MemoryInput) IsKeyJustReleased(key ebiten.Key) bool {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	return m.keyReleased[key]
+}
+
+// KeyPressDuration returns the amount of ticks the key is held down.
+func (m *MemoryInput) KeyPressDuration(key ebiten.Key) int {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	return m.keyDuration[key]
+}
+
+// InputChars returns the chars that were typed in this tick.
+func (m *MemoryInput) InputChars() []rune {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	return append([]rune(nil), m.chars...)
+}
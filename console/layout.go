@@ -0,0 +1,217 @@
+package console
+
+import "unicode"
+
+// Alignment specifies the horizontal alignment of printed text.
+type Alignment int
+
+const (
+	// AlignLeft aligns the lines at the left bound.
+	AlignLeft = Alignment(0)
+	// AlignCenter centers the lines between the left and right bound.
+	AlignCenter = Alignment(1)
+	// AlignRight aligns the lines at the right bound.
+	AlignRight = Alignment(2)
+	// AlignJustify stretches wrapped lines to fill the whole width by widening the spaces
+	// between words. The last line of a paragraph is aligned left.
+	AlignJustify = Alignment(3)
+)
+
+// VerticalAlignment specifies the vertical alignment of printed text.
+type VerticalAlignment int
+
+const (
+	// AlignTop aligns the text at the top bound.
+	AlignTop = VerticalAlignment(0)
+	// AlignMiddle centers the text between the top and bottom bound.
+	AlignMiddle = VerticalAlignment(1)
+	// AlignBottom aligns the text at the bottom bound.
+	AlignBottom = VerticalAlignment(2)
+)
+
+// PrintOptions specifies how a text is laid out by PrintWith.
+type PrintOptions struct {
+	// WordWrap breaks lines between words instead of at exactly the width.
+	WordWrap bool
+	// Hyphenate breaks words that are longer than the width with a hyphen.
+	// Without it long words are split at exactly the width.
+	Hyphenate bool
+	// Align is the horizontal alignment of the lines.
+	Align Alignment
+	// VerticalAlign is the vertical alignment of the lines inside the height.
+	VerticalAlign VerticalAlignment
+	// Offset is the amount of lines that are skipped at the top.
+	Offset int
+}
+
+// layoutChar is a single char in a laid out text.
+type layoutChar struct {
+	char rune
+	// glyph is true if char is the char of a font glyph and not a rune.
+	glyph bool
+	// span is the index of the span the char belongs to.
+	span int
+}
+
+func (c layoutChar) isSpace() bool {
+	return !c.glyph && unicode.IsSpace(c.char)
+}
+
+// layoutLine is a single line of a laid out text.
+type layoutLine struct {
+	chars []layoutChar
+	// wrapped is true if the line was broken because it reached the width
+	// and not because the paragraph ended.
+	wrapped bool
+}
+
+// layoutText breaks the text of the spans into lines that fit into the width.
+// If the width is <= 0 lines are only broken at newlines.
+func layoutText(spans Spans, width int, options PrintOptions) []layoutLine {
+	var lines []layoutLine
+	var paragraph []layoutChar
+
+	for i := range spans {
+		if spans[i].IsGlyph {
+			paragraph = append(paragraph, layoutChar{char: rune(spans[i].Glyph), glyph: true, span: i})
+			continue
+		}
+
+		for _, char := range spans[i].Text {
+			if char == '\n' {
+				lines = append(lines, layoutParagraph(paragraph, width, options)...)
+				paragraph = paragraph[:0]
+				continue
+			}
+			paragraph = append(paragraph, layoutChar{char: char, span: i})
+		}
+	}
+
+	return append(lines, layoutParagraph(paragraph, width, options)...)
+}
+
+// layoutParagraph breaks a paragraph without newlines into lines.
+func layoutParagraph(chars []layoutChar, width int, options PrintOptions) []layoutLine {
+	if width <= 0 || len(chars) <= width {
+		return []layoutLine{{chars: append([]layoutChar(nil), chars...)}}
+	}
+
+	if !options.WordWrap {
+		var lines []layoutLine
+		for len(chars) > width {
+			lines = append(lines, layoutLine{chars: append([]layoutChar(nil), chars[:width]...), wrapped: true})
+			chars = chars[width:]
+		}
+		return append(lines, layoutLine{chars: append([]layoutChar(nil), chars...)})
+	}
+
+	var lines []layoutLine
+	var line []layoutChar
+	var spaces []layoutChar
+
+	pushLine := func() {
+		lines = append(lines, layoutLine{chars: line, wrapped: true})
+		line = nil
+	}
+
+	for start := 0; start < len(chars); {
+		end := start
+		isSpace := chars[start].isSpace()
+		for end < len(chars) && chars[end].isSpace() == isSpace {
+			end++
+		}
+		token := chars[start:end]
+		start = end
+
+		if isSpace {
+			spaces = token
+			continue
+		}
+
+		// Spaces at the start of a paragraph are kept as indentation, but
+		// spaces at the start of a wrapped line are dropped.
+		if len(line) == 0 && len(lines) > 0 {
+			spaces = nil
+		}
+
+		if len(line)+len(spaces)+len(token) <= width {
+			line = append(line, spaces...)
+			line = append(line, token...)
+			spaces = nil
+			continue
+		}
+
+		if len(line) > 0 {
+			pushLine()
+		}
+		spaces = nil
+
+		for len(token) > width {
+			if options.Hyphenate && width > 1 {
+				line = append(line, token[:width-1]...)
+				line = append(line, layoutChar{char: '-', span: token[width-2].span})
+				token = token[width-1:]
+			} else {
+				line = append(line, token[:width]...)
+				token = token[width:]
+			}
+			pushLine()
+		}
+		line = append(line, token...)
+	}
+
+	return append(lines, layoutLine{chars: line})
+}
+
+// alignLine returns the x offset of every char in the line for the given alignment.
+func alignLine(line layoutLine, width int, align Alignment) []int {
+	positions := make([]int, len(line.chars))
+
+	offset := 0
+	switch align {
+	case AlignCenter:
+		offset = (width - len(line.chars)) / 2
+	case AlignRight:
+		offset = width - len(line.chars)
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	var gaps []int
+	if align == AlignJustify && line.wrapped {
+		for i := 1; i < len(line.chars)-1; i++ {
+			if line.chars[i].isSpace() && !line.chars[i-1].isSpace() {
+				gaps = append(gaps, i)
+			}
+		}
+	}
+
+	extra := width - len(line.chars)
+	gap := 0
+	for i := range line.chars {
+		if gap < len(gaps) && gaps[gap] == i && extra > 0 {
+			share := extra / (len(gaps) - gap)
+			if extra%(len(gaps)-gap) > 0 {
+				share++
+			}
+			offset += share
+			extra -= share
+			gap++
+		}
+		positions[i] = offset + i
+	}
+
+	return positions
+}
+
+// maxLineWidth returns the length of the longest line.
+func maxLineWidth(lines []layoutLine) int {
+	width := 0
+	for i := range lines {
+		if len(lines[i].chars) > width {
+			width = len(lines[i].chars)
+		}
+	}
+	return width
+}
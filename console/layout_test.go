@@ -0,0 +1,162 @@
+package console
+
+import (
+	"testing"
+
+	"github.com/BigJk/ramen/concolor"
+	"github.com/BigJk/ramen/t"
+	"github.com/stretchr/testify/assert"
+)
+
+func layoutStrings(lines []layoutLine) []string {
+	var res []string
+	for i := range lines {
+		var line []rune
+		for _, char := range lines[i].chars {
+			line = append(line, char.char)
+		}
+		res = append(res, string(line))
+	}
+	return res
+}
+
+func TestLayoutText(t *testing.T) {
+	cases := []struct {
+		name    string
+		text    string
+		width   int
+		options PrintOptions
+		lines   []string
+	}{
+		{"Empty", "", 5, PrintOptions{}, []string{""}},
+		{"Unbounded", "hello world\nsecond", 0, PrintOptions{}, []string{"hello world", "second"}},
+		{"HardBreak", "abcdefg", 3, PrintOptions{}, []string{"abc", "def", "g"}},
+		{"WordWrap", "the quick brown fox", 10, PrintOptions{WordWrap: true}, []string{"the quick", "brown fox"}},
+		{"Indentation", "  ab cd", 5, PrintOptions{WordWrap: true}, []string{"  ab", "cd"}},
+		{"DropSpacesAfterWrap", "ab    cd", 3, PrintOptions{WordWrap: true}, []string{"ab", "cd"}},
+		{"LongWord", "abcdefgh xy", 3, PrintOptions{WordWrap: true}, []string{"abc", "def", "gh", "xy"}},
+		{"Hyphenate", "abcdefgh", 4, PrintOptions{WordWrap: true, Hyphenate: true}, []string{"abc-", "def-", "gh"}},
+		{"Newlines", "ab cd\n\nef", 3, PrintOptions{WordWrap: true}, []string{"ab", "cd", "", "ef"}},
+		{"Markup", "[[f:red]]ab[[/]] cd", 2, PrintOptions{WordWrap: true}, []string{"ab", "cd"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.lines, layoutStrings(layoutText(ParseMarkup(c.text), c.width, c.options)))
+		})
+	}
+}
+
+func TestAlignLine(t *testing.T) {
+	line := func(text string, wrapped bool) layoutLine {
+		l := layoutLine{wrapped: wrapped}
+		for _, char := range text {
+			l.chars = append(l.chars, layoutChar{char: char})
+		}
+		return l
+	}
+
+	cases := []struct {
+		name      string
+		line      layoutLine
+		width     int
+		align     Alignment
+		positions []int
+	}{
+		{"Left", line("ab", false), 6, AlignLeft, []int{0, 1}},
+		{"Center", line("ab", false), 6, AlignCenter, []int{2, 3}},
+		{"Right", line("ab", false), 6, AlignRight, []int{4, 5}},
+		{"TooWide", line("abcd", false), 2, AlignRight, []int{0, 1, 2, 3}},
+		{"Justify", line("a b c", true), 7, AlignJustify, []int{0, 2, 3, 5, 6}},
+		{"JustifyUneven", line("a b c", true), 8, AlignJustify, []int{0, 3, 4, 6, 7}},
+		{"JustifyLastLine", line("a b c", false), 7, AlignJustify, []int{0, 1, 2, 3, 4}},
+		{"JustifySingleWord", line("abc", true), 7, AlignJustify, []int{0, 1, 2}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.positions, alignLine(c.line, c.width, c.align))
+		})
+	}
+}
+
+func TestPrintWith(t *testing.T) {
+	cases := []struct {
+		name    string
+		width   int
+		height  int
+		options PrintOptions
+		text    string
+		lines   int
+		rows    []string
+	}{
+		{"Plain", 0, 0, PrintOptions{}, "ab\ncd", 2, []string{"ab   ", "cd   ", "     "}},
+		{"Offset", 0, 0, PrintOptions{Offset: 1}, "ab\ncd", 1, []string{"cd   ", "     ", "     "}},
+		{"NegativeOffset", 0, 0, PrintOptions{Offset: -1}, "ab\ncd", 2, []string{"ab   ", "cd   ", "     "}},
+		{"OffsetPastEnd", 0, 0, PrintOptions{Offset: 5}, "ab\ncd", 0, []string{"     ", "     ", "     "}},
+		{"Height", 0, 1, PrintOptions{}, "ab\ncd", 2, []string{"ab   ", "     ", "     "}},
+		{"AlignRight", 5, 0, PrintOptions{Align: AlignRight}, "ab", 1, []string{"   ab", "     ", "     "}},
+		{"AlignBottom", 5, 3, PrintOptions{VerticalAlign: AlignBottom}, "ab", 1, []string{"     ", "     ", "ab   "}},
+		{"AlignMiddle", 5, 3, PrintOptions{Align: AlignCenter, VerticalAlign: AlignMiddle}, "ab", 1, []string{"     ", " ab  ", "     "}},
+		{"Justify", 5, 0, PrintOptions{WordWrap: true, Align: AlignJustify}, "a b c d", 2, []string{"a b c", "d    ", "     "}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			con, err := New(5, 3, newTestFont(), "")
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, c.lines, con.PrintWith(0, 0, c.width, c.height, c.options, c.text))
+			assert.Equal(t, c.lines, con.CalcTextHeightWith(c.width, c.height, c.options, c.text))
+
+			assert.Equal(t, c.rows, charRows(t, con))
+		})
+	}
+}
+
+func TestPrintJustifyGaps(test *testing.T) {
+	con, err := New(5, 2, newTestFont(), "")
+	if !assert.NoError(test, err) {
+		return
+	}
+
+	red := concolor.RGB(255, 0, 0)
+	assert.NoError(test, con.TransformAll(t.Char('x')))
+	con.PrintWith(0, 0, 5, 0, PrintOptions{WordWrap: true, Align: AlignJustify}, "ab c dd", t.Background(red))
+
+	// The widened gaps overwrite the old content like the spaces do.
+	assert.Equal(test, []string{"ab  c", "ddxxx"}, charRows(test, con))
+	for x := 2; x < 4; x++ {
+		cell, err := con.Get(x, 0)
+		if assert.NoError(test, err) {
+			assert.Equal(test, red, cell.Background, "cell %d,0", x)
+		}
+	}
+}
+
+func TestPrintGradient(test *testing.T) {
+	con, err := New(8, 3, newTestFont(), "")
+	if !assert.NoError(test, err) {
+		return
+	}
+
+	black, white := concolor.RGB(0, 0, 0), concolor.RGB(255, 255, 255)
+	con.PrintWith(0, 0, 8, 0, PrintOptions{Align: AlignCenter}, "abc\nabcde", t.ForegroundGradient(black, white, 0))
+
+	// The gradient spans the printed text and not the whole width.
+	for _, c := range []struct {
+		x, y int
+		col  concolor.Color
+	}{
+		{1, 1, black},
+		{2, 0, concolor.RGB(64, 64, 64)},
+		{3, 1, concolor.RGB(128, 128, 128)},
+		{5, 1, white},
+	} {
+		cell, err := con.Get(c.x, c.y)
+		assert.NoError(test, err)
+		assert.Equal(test, c.col, cell.Foreground, "cell %d,%d", c.x, c.y)
+	}
+}
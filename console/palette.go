@@ -0,0 +1,74 @@
+package console
+
+import (
+	"sync/atomic"
+
+	"github.com/BigJk/ramen"
+	"github.com/BigJk/ramen/concolor"
+)
+
+// paletteGeneration is increased every time a palette is set, so that cached
+// consoles notice when the palette they were rendered with has changed.
+var paletteGeneration uint64
+
+// SetPalette sets the palette that is used to resolve the palette indices of the cells.
+// Sub-consoles without a palette of their own use the palette of their parent, so
+// swapping the palette of the root console recolors everything at once. Passing nil
+// removes the palette. Call SetPalette again after changing the colors of the active
+// palette to redraw the cells.
+func (c *Console) SetPalette(palette *concolor.Palette) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.palette = palette
+	c.paletteGen = atomic.AddUint64(&paletteGeneration, 1)
+}
+
+// Palette returns the palette of the console or nil if it has none.
+func (c *Console) Palette() *concolor.Palette {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.palette
+}
+
+// activePalette returns the palette that is used to draw the console, which is the
+// palette of the console or of the closest parent that has one, and the latest
+// generation of all palettes that were set on the way.
+func (c *Console) activePalette() (*concolor.Palette, uint64) {
+	gen := uint64(0)
+	for con := c; con != nil; {
+		con.mtx.RLock()
+		palette, parent := con.palette, con.parent
+		if con.paletteGen > gen {
+			gen = con.paletteGen
+		}
+		con.mtx.RUnlock()
+
+		if palette != nil {
+			return palette, gen
+		}
+		con = parent
+	}
+	return nil, gen
+}
+
+// lockedPalette returns the active palette like activePalette, but expects the
+// lock of the console to be held already.
+func (c *Console) lockedPalette() *concolor.Palette {
+	if c.palette != nil || c.parent == nil {
+		return c.palette
+	}
+
+	palette, _ := c.parent.activePalette()
+	return palette
+}
+
+// resolveColors returns the foreground and background color of the cell with the
+// palette indices resolved.
+func resolveColors(palette *concolor.Palette, cell ramen.Cell) (concolor.Color, concolor.Color) {
+	if palette == nil {
+		return cell.Foreground, cell.Background
+	}
+	return palette.Resolve(cell.ForegroundIndex, cell.Foreground), palette.Resolve(cell.BackgroundIndex, cell.Background)
+}
@@ -0,0 +1,107 @@
+package console
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/BigJk/ramen/concolor"
+	"github.com/BigJk/ramen/t"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlendPaletteIndexedCell(test *testing.T) {
+	con, err := New(2, 1, newTestFont(), "")
+	if !assert.NoError(test, err) {
+		return
+	}
+
+	sub, err := con.CreateSubConsole(1, 0, 1, 1)
+	if !assert.NoError(test, err) {
+		return
+	}
+
+	con.SetPalette(concolor.NewPalette("test", concolor.RGB(200, 0, 0), concolor.RGB(0, 0, 200)))
+
+	// The blend starts from the palette color and not from the fallback color.
+	assert.NoError(test, con.Transform(0, 0, t.Char(-1), t.Background(concolor.White), t.BackgroundIndex(0)))
+	assert.NoError(test, con.Transform(0, 0, t.LerpBackground(concolor.Black, 0.5)))
+
+	// Sub-consoles resolve the palette of their parent.
+	assert.NoError(test, sub.Transform(0, 0, t.Char(-1), t.BackgroundIndex(1), t.ForegroundIndex(0)))
+	assert.NoError(test, sub.Transform(0, 0, t.BlendBackground(concolor.RGB(0, 200, 0), concolor.BlendAdd)))
+
+	cell, err := con.Get(0, 0)
+	if assert.NoError(test, err) {
+		assert.Equal(test, concolor.RGB(100, 0, 0), cell.Background)
+		assert.Equal(test, concolor.PaletteIndex(0), cell.BackgroundIndex)
+	}
+
+	cell, err = sub.Get(0, 0)
+	if assert.NoError(test, err) {
+		assert.Equal(test, concolor.RGB(0, 200, 200), cell.Background)
+		assert.Equal(test, concolor.PaletteIndex(0), cell.BackgroundIndex)
+		assert.Equal(test, concolor.Index(0), cell.ForegroundIndex)
+	}
+
+	// The blended colors stay when the palette is swapped.
+	con.SetPalette(concolor.NewPalette("swap", concolor.White, concolor.White))
+	img := con.RenderImage()
+	assert.Equal(test, color.RGBA{R: 100, A: 255}, img.RGBAAt(0, 0))
+	assert.Equal(test, color.RGBA{G: 200, B: 200, A: 255}, img.RGBAAt(2, 0))
+}
+
+func TestBlitPaletteIndexedCell(test *testing.T) {
+	dst, err := New(3, 1, newTestFont(), "")
+	if !assert.NoError(test, err) {
+		return
+	}
+
+	src, err := New(1, 1, newTestFont(), "")
+	if !assert.NoError(test, err) {
+		return
+	}
+
+	sub, err := dst.CreateSubConsole(0, 0, 1, 1)
+	if !assert.NoError(test, err) {
+		return
+	}
+
+	dst.SetPalette(concolor.NewPalette("dst", concolor.RGB(200, 0, 0), concolor.RGB(0, 0, 200)))
+	src.SetPalette(concolor.NewPalette("src", concolor.RGB(0, 200, 0)))
+
+	// Opaque cells of another palette are copied with their palette colors.
+	assert.NoError(test, src.Transform(0, 0, t.Char('a'), t.ForegroundIndex(0), t.BackgroundIndex(0)))
+	assert.NoError(test, src.Blit(dst, image.Rect(0, 0, 1, 1), 0, 0, 1, 1))
+
+	cell, err := dst.Get(0, 0)
+	if assert.NoError(test, err) {
+		assert.Equal(test, concolor.RGB(0, 200, 0), cell.Foreground)
+		assert.Equal(test, concolor.RGB(0, 200, 0), cell.Background)
+		assert.Equal(test, concolor.PaletteIndex(0), cell.ForegroundIndex)
+		assert.Equal(test, concolor.PaletteIndex(0), cell.BackgroundIndex)
+	}
+
+	// Blending starts from the palette colors of the destination.
+	assert.NoError(test, dst.Transform(1, 0, t.Char('a'), t.ForegroundIndex(1), t.BackgroundIndex(0)))
+	assert.NoError(test, src.Transform(0, 0, t.Char('a'), t.Foreground(concolor.White), t.Background(concolor.White)))
+	assert.NoError(test, src.Blit(dst, image.Rect(0, 0, 1, 1), 1, 0, 0.5, 0.5))
+
+	cell, err = dst.Get(1, 0)
+	if assert.NoError(test, err) {
+		assert.Equal(test, concolor.RGB(0, 0, 200).Lerp(concolor.White, 0.5), cell.Foreground)
+		assert.Equal(test, concolor.RGB(200, 0, 0).Lerp(concolor.White, 0.5), cell.Background)
+		assert.Equal(test, concolor.PaletteIndex(0), cell.ForegroundIndex)
+		assert.Equal(test, concolor.PaletteIndex(0), cell.BackgroundIndex)
+	}
+
+	// Consoles with the same palette keep the references of opaque cells.
+	assert.NoError(test, sub.Transform(0, 0, t.Char('a'), t.ForegroundIndex(1), t.BackgroundIndex(0)))
+	assert.NoError(test, sub.Blit(dst, image.Rect(0, 0, 1, 1), 2, 0, 1, 1))
+
+	cell, err = dst.Get(2, 0)
+	if assert.NoError(test, err) {
+		assert.Equal(test, concolor.Index(1), cell.ForegroundIndex)
+		assert.Equal(test, concolor.Index(0), cell.BackgroundIndex)
+	}
+}
@@ -0,0 +1,55 @@
+package console
+
+import "fmt"
+
+// Position returns the position of the sub-console relative to its parent.
+func (c *Console) Position() (int, int) {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.x, c.y
+}
+
+// SetPosition moves the sub-console to the given position relative to its parent.
+// The sub-console is allowed to be partially outside of its parent, in which case
+// it will be clipped.
+func (c *Console) SetPosition(x, y int) error {
+	if !c.isSubConsole {
+		return fmt.Errorf("position of the main console can't be changed")
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.x = x
+	c.y = y
+	return nil
+}
+
+// Move moves the sub-console by dx, dy cells.
+func (c *Console) Move(dx, dy int) error {
+	x, y := c.Position()
+	return c.SetPosition(x+dx, y+dy)
+}
+
+// SetVisible shows or hides the sub-console. Hidden sub-consoles and their children
+// won't be drawn, don't receive the mouse position and their components won't be updated.
+func (c *Console) SetVisible(visible bool) error {
+	if !c.isSubConsole {
+		return fmt.Errorf("visibility of the main console can't be changed")
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.hidden = !visible
+	return nil
+}
+
+// IsVisible returns true if the console is visible.
+func (c *Console) IsVisible() bool {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return !c.hidden
+}
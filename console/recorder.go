@@ -0,0 +1,289 @@
+package console
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"io"
+	"math"
+	"os"
+	"sync"
+)
+
+// Recorder captures frames of a console over time and writes them as
+// animated GIF or APNG.
+type Recorder struct {
+	con      *Console
+	interval float64
+
+	mtx     sync.Mutex
+	elapsed float64
+	frames  []*image.RGBA
+	delays  []float64
+}
+
+// NewRecorder creates a recorder for the console that captures a frame every
+// interval seconds when Tick is called.
+func NewRecorder(con *Console, interval float64) *Recorder {
+	return &Recorder{
+		con:      con,
+		interval: interval,
+	}
+}
+
+// Tick advances the time of the recorder and captures a frame if the interval
+// has passed. Call this from the tick hook of the console with the elapsed time.
+func (r *Recorder) Tick(timeElapsed float64) error {
+	r.mtx.Lock()
+	r.elapsed += timeElapsed
+	capture := len(r.frames) == 0 || r.elapsed >= r.interval
+	r.mtx.Unlock()
+
+	if capture {
+		return r.Capture()
+	}
+	return nil
+}
+
+// Capture captures a frame of the console right now. The time since the last
+// captured frame is used as delay of the previous frame. All frames of a recording
+// need the same size, so capturing fails if the console was resized since the
+// first frame. Call Reset to start a new recording with the new size.
+func (r *Recorder) Capture() error {
+	frame := r.con.RenderImage()
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if len(r.frames) > 0 && frame.Bounds() != r.frames[0].Bounds() {
+		return fmt.Errorf("frame size %v differs from the recorded size %v", frame.Bounds().Size(), r.frames[0].Bounds().Size())
+	}
+
+	if len(r.delays) > 0 {
+		r.delays[len(r.delays)-1] = r.elapsed
+	}
+
+	r.frames = append(r.frames, frame)
+	r.delays = append(r.delays, r.interval)
+	r.elapsed = 0
+	return nil
+}
+
+// Frames returns the amount of captured frames.
+func (r *Recorder) Frames() int {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	return len(r.frames)
+}
+
+// Reset removes all captured frames.
+func (r *Recorder) Reset() {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	r.frames = nil
+	r.delays = nil
+	r.elapsed = 0
+}
+
+// WriteGIF writes the captured frames as animated GIF. If a frame contains more
+// than 256 colors it will be dithered.
+func (r *Recorder) WriteGIF(w io.Writer) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if len(r.frames) == 0 {
+		return fmt.Errorf("no frames captured")
+	}
+
+	anim := gif.GIF{}
+	for i := range r.frames {
+		bounds := r.frames[i].Bounds()
+
+		var paletted *image.Paletted
+		if pal := exactPalette(r.frames[i], 256); pal != nil {
+			paletted = image.NewPaletted(bounds, pal)
+			draw.Draw(paletted, bounds, r.frames[i], bounds.Min, draw.Src)
+		} else {
+			paletted = image.NewPaletted(bounds, palette.Plan9)
+			draw.FloydSteinberg.Draw(paletted, bounds, r.frames[i], bounds.Min)
+		}
+
+		anim.Image = append(anim.Image, paletted)
+		anim.Delay = append(anim.Delay, int(math.Round(r.delays[i]*100)))
+	}
+
+	return gif.EncodeAll(w, &anim)
+}
+
+// WriteAPNG writes the captured frames as animated PNG.
+func (r *Recorder) WriteAPNG(w io.Writer) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if len(r.frames) == 0 {
+		return fmt.Errorf("no frames captured")
+	}
+
+	bounds := r.frames[0].Bounds()
+	enc := apngEncoder{w: w}
+
+	enc.write([]byte("\x89PNG\r\n\x1a\n"))
+
+	ihdr := make([]byte, 13)
+	binary.BigEndian.PutUint32(ihdr[0:], uint32(bounds.Dx()))
+	binary.BigEndian.PutUint32(ihdr[4:], uint32(bounds.Dy()))
+	ihdr[8] = 8 // bit depth
+	ihdr[9] = 6 // true color with alpha
+	enc.writeChunk("IHDR", ihdr)
+
+	actl := make([]byte, 8)
+	binary.BigEndian.PutUint32(actl[0:], uint32(len(r.frames)))
+	enc.writeChunk("acTL", actl)
+
+	for i := range r.frames {
+		fctl := make([]byte, 26)
+		binary.BigEndian.PutUint32(fctl[0:], enc.nextSequence())
+		binary.BigEndian.PutUint32(fctl[4:], uint32(bounds.Dx()))
+		binary.BigEndian.PutUint32(fctl[8:], uint32(bounds.Dy()))
+		binary.BigEndian.PutUint16(fctl[20:], uint16(math.Min(math.Round(r.delays[i]*1000), math.MaxUint16)))
+		binary.BigEndian.PutUint16(fctl[22:], 1000)
+		enc.writeChunk("fcTL", fctl)
+
+		data, err := compressFrame(r.frames[i])
+		if err != nil {
+			return err
+		}
+
+		if i == 0 {
+			enc.writeChunk("IDAT", data)
+		} else {
+			fdat := make([]byte, 4, 4+len(data))
+			binary.BigEndian.PutUint32(fdat, enc.nextSequence())
+			enc.writeChunk("fdAT", append(fdat, data...))
+		}
+	}
+
+	enc.writeChunk("IEND", nil)
+
+	return enc.err
+}
+
+// SaveGIF saves the captured frames as animated GIF file.
+func (r *Recorder) SaveGIF(path string) error {
+	return r.save(path, r.WriteGIF)
+}
+
+// SaveAPNG saves the captured frames as animated PNG file.
+func (r *Recorder) SaveAPNG(path string) error {
+	return r.save(path, r.WriteAPNG)
+}
+
+func (r *Recorder) save(path string, write func(w io.Writer) error) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := write(file); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// exactPalette returns all the colors of the image or nil if the image
+// contains more than max colors.
+func exactPalette(img *image.RGBA, max int) color.Palette {
+	seen := map[color.RGBA]bool{}
+	var pal color.Palette
+
+	for i := 0; i+3 < len(img.Pix); i += 4 {
+		c := color.RGBA{R: img.Pix[i], G: img.Pix[i+1], B: img.Pix[i+2], A: img.Pix[i+3]}
+		if seen[c] {
+			continue
+		}
+
+		if len(pal) >= max {
+			return nil
+		}
+
+		seen[c] = true
+		pal = append(pal, c)
+	}
+
+	return pal
+}
+
+// compressFrame returns the zlib compressed scanlines of the image as 8-bit
+// non-premultiplied RGBA without filtering.
+func compressFrame(img *image.RGBA) ([]byte, error) {
+	bounds := img.Bounds()
+
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+
+	line := make([]byte, 1+bounds.Dx()*4)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.RGBAAt(x, y)).(color.NRGBA)
+			i := 1 + (x-bounds.Min.X)*4
+			line[i], line[i+1], line[i+2], line[i+3] = c.R, c.G, c.B, c.A
+		}
+
+		if _, err := zw.Write(line); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// apngEncoder writes png chunks and keeps track of the first error.
+type apngEncoder struct {
+	w        io.Writer
+	err      error
+	sequence uint32
+}
+
+func (e *apngEncoder) nextSequence() uint32 {
+	e.sequence++
+	return e.sequence - 1
+}
+
+func (e *apngEncoder) write(data []byte) {
+	if e.err != nil {
+		return
+	}
+	_, e.err = e.w.Write(data)
+}
+
+func (e *apngEncoder) writeChunk(name string, data []byte) {
+	header := make([]byte, 8)
+	binary.BigEndian.PutUint32(header, uint32(len(data)))
+	copy(header[4:], name)
+
+	crc := crc32.NewIEEE()
+	_, _ = crc.Write(header[4:])
+	_, _ = crc.Write(data)
+
+	footer := make([]byte, 4)
+	binary.BigEndian.PutUint32(footer, crc.Sum32())
+
+	e.write(header)
+	e.write(data)
+	e.write(footer)
+}
@@ -0,0 +1,104 @@
+package console
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image/gif"
+	"image/png"
+	"testing"
+
+	"github.com/BigJk/ramen/concolor"
+	"github.com/BigJk/ramen/t"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRecording(test *testing.T) (*Console, *Recorder) {
+	con, err := New(3, 2, newTestFont(), "")
+	if !assert.NoError(test, err) {
+		test.FailNow()
+	}
+
+	rec := NewRecorder(con, 0.5)
+	for i, elapsed := range []float64{0.1, 0.7, 0.2, 0.3} {
+		assert.NoError(test, con.TransformAll(t.Char(-1), t.Background(concolor.RGB(byte(i*50), 0, 0))))
+		assert.NoError(test, rec.Tick(elapsed))
+	}
+
+	return con, rec
+}
+
+func TestRecorderGIF(test *testing.T) {
+	_, rec := newTestRecording(test)
+	assert.Equal(test, 3, rec.Frames())
+
+	var buf bytes.Buffer
+	if !assert.NoError(test, rec.WriteGIF(&buf)) {
+		return
+	}
+
+	anim, err := gif.DecodeAll(&buf)
+	if !assert.NoError(test, err) {
+		return
+	}
+
+	assert.Len(test, anim.Image, 3)
+	assert.Equal(test, []int{70, 50, 50}, anim.Delay)
+	assert.Equal(test, 6, anim.Config.Width)
+	assert.Equal(test, 4, anim.Config.Height)
+
+	r, _, _, _ := anim.Image[2].At(0, 0).RGBA()
+	assert.Equal(test, uint32(150), r>>8)
+}
+
+func TestRecorderAPNG(test *testing.T) {
+	_, rec := newTestRecording(test)
+
+	var buf bytes.Buffer
+	if !assert.NoError(test, rec.WriteAPNG(&buf)) {
+		return
+	}
+
+	// The default image is the first frame, which any png decoder can read.
+	img, err := png.Decode(bytes.NewReader(buf.Bytes()))
+	if assert.NoError(test, err) {
+		assert.Equal(test, 6, img.Bounds().Dx())
+		assert.Equal(test, 4, img.Bounds().Dy())
+	}
+
+	data := buf.Bytes()[8:]
+	var chunks []string
+	var delays []uint16
+	for len(data) >= 12 {
+		length := int(binary.BigEndian.Uint32(data))
+		name, body := string(data[4:8]), data[8:8+length]
+		assert.Equal(test, crc32.ChecksumIEEE(data[4:8+length]), binary.BigEndian.Uint32(data[8+length:]), "crc of %s", name)
+
+		switch name {
+		case "acTL":
+			assert.Equal(test, uint32(3), binary.BigEndian.Uint32(body))
+		case "fcTL":
+			assert.Equal(test, uint32(6), binary.BigEndian.Uint32(body[4:]))
+			assert.Equal(test, uint32(4), binary.BigEndian.Uint32(body[8:]))
+			delays = append(delays, binary.BigEndian.Uint16(body[20:]))
+		}
+
+		chunks = append(chunks, name)
+		data = data[12+length:]
+	}
+
+	assert.Equal(test, []string{"IHDR", "acTL", "fcTL", "IDAT", "fcTL", "fdAT", "fcTL", "fdAT", "IEND"}, chunks)
+	assert.Equal(test, []uint16{700, 500, 500}, delays)
+}
+
+func TestRecorderRejectsResizedFrames(test *testing.T) {
+	con, rec := newTestRecording(test)
+
+	assert.NoError(test, con.Resize(4, 2))
+	assert.Error(test, rec.Capture())
+	assert.Equal(test, 3, rec.Frames())
+
+	rec.Reset()
+	assert.NoError(test, rec.Capture())
+	assert.Equal(test, 1, rec.Frames())
+}
@@ -0,0 +1,119 @@
+package console
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/BigJk/ramen/concolor"
+	"github.com/BigJk/ramen/font"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// Renderer represents a target that the cells of a console can be drawn onto.
+// All positions and sizes are given in pixels.
+type Renderer interface {
+	// DrawBackground fills the given area with the background color.
+	DrawBackground(x, y, width, height int, col concolor.Color)
+
+	// DrawChar draws a char of the font at the given position. If the char
+	// is not a colored tile it will be tinted with the foreground color.
+	DrawChar(font *font.Font, char int, x, y int, foreground concolor.Color)
+}
+
+// EbitenRenderer renders onto an ebiten image.
+type EbitenRenderer struct {
+	Target *ebiten.Image
+}
+
+// NewEbitenRenderer creates a new renderer that draws onto the given ebiten image.
+func NewEbitenRenderer(target *ebiten.Image) *EbitenRenderer {
+	return &EbitenRenderer{Target: target}
+}
+
+// DrawBackground fills the given area with the background color.
+func (r *EbitenRenderer) DrawBackground(x, y, width, height int, col concolor.Color) {
+	ebitenutil.DrawRect(r.Target, float64(x), float64(y), float64(width), float64(height), col)
+}
+
+// DrawChar draws a char of the font at the given position.
+func (r *EbitenRenderer) DrawChar(font *font.Font, char int, x, y int, foreground concolor.Color) {
+	charImage := font.ToSubImage(char)
+	if charImage == nil {
+		return
+	}
+
+	op := ebiten.DrawImageOptions{}
+	if !font.IsTile(char) {
+		op.ColorM.Scale(foreground.Floats())
+	}
+	op.GeoM.Translate(float64(x), float64(y))
+	r.Target.DrawImage(charImage, &op)
+}
+
+// ImageRenderer renders into a in-memory RGBA image without the need
+// of a graphics context. This can be used to create snapshots of a console
+// in headless environments.
+type ImageRenderer struct {
+	Image *image.RGBA
+}
+
+// NewImageRenderer creates a new renderer with a transparent image of the given pixel size.
+func NewImageRenderer(width, height int) *ImageRenderer {
+	return &ImageRenderer{Image: image.NewRGBA(image.Rect(0, 0, width, height))}
+}
+
+// DrawBackground fills the given area with the background color.
+func (r *ImageRenderer) DrawBackground(x, y, width, height int, col concolor.Color) {
+	area := image.Rect(x, y, x+width, y+height).Intersect(r.Image.Bounds())
+	c := color.NRGBA{R: col.R, G: col.G, B: col.B, A: col.A}
+
+	for py := area.Min.Y; py < area.Max.Y; py++ {
+		for px := area.Min.X; px < area.Max.X; px++ {
+			r.blend(px, py, c)
+		}
+	}
+}
+
+// DrawChar draws a char of the font at the given position.
+func (r *ImageRenderer) DrawChar(font *font.Font, char int, x, y int, foreground concolor.Color) {
+	src, ok := font.ToSubRect(char)
+	if !ok {
+		return
+	}
+
+	tile := font.IsTile(char)
+	for sy := src.Min.Y; sy < src.Max.Y; sy++ {
+		for sx := src.Min.X; sx < src.Max.X; sx++ {
+			px, py := x+sx-src.Min.X, y+sy-src.Min.Y
+			if !(image.Point{X: px, Y: py}).In(r.Image.Bounds()) {
+				continue
+			}
+
+			c := color.NRGBAModel.Convert(font.Source.At(sx, sy)).(color.NRGBA)
+			if !tile {
+				c.R = uint8(uint16(c.R) * uint16(foreground.R) / 0xff)
+				c.G = uint8(uint16(c.G) * uint16(foreground.G) / 0xff)
+				c.B = uint8(uint16(c.B) * uint16(foreground.B) / 0xff)
+				c.A = uint8(uint16(c.A) * uint16(foreground.A) / 0xff)
+			}
+			r.blend(px, py, c)
+		}
+	}
+}
+
+// blend composites the color over the pixel at the given position.
+func (r *ImageRenderer) blend(x, y int, c color.NRGBA) {
+	if c.A == 0 {
+		return
+	}
+
+	i := r.Image.PixOffset(x, y)
+	pix := r.Image.Pix[i : i+4 : i+4]
+
+	a := uint32(c.A)
+	pix[0] = uint8((uint32(c.R)*a + uint32(pix[0])*(0xff-a)) / 0xff)
+	pix[1] = uint8((uint32(c.G)*a + uint32(pix[1])*(0xff-a)) / 0xff)
+	pix[2] = uint8((uint32(c.B)*a + uint32(pix[2])*(0xff-a)) / 0xff)
+	pix[3] = uint8(a + uint32(pix[3])*(0xff-a)/0xff)
+}
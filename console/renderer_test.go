@@ -0,0 +1,80 @@
+package console
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/BigJk/ramen/concolor"
+	"github.com/BigJk/ramen/font"
+	"github.com/BigJk/ramen/t"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestFont creates a font with 2x2 tiles. Char 0 only has its top left pixel
+// set, char 1 is a solid blue tile.
+func newTestFont() *font.Font {
+	src := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	src.SetNRGBA(0, 0, color.NRGBA{R: 255, G: 255, B: 255, A: 255})
+	for x := 2; x < 4; x++ {
+		for y := 0; y < 2; y++ {
+			src.SetNRGBA(x, y, color.NRGBA{B: 255, A: 255})
+		}
+	}
+
+	return &font.Font{
+		Source:     src,
+		TileWidth:  2,
+		TileHeight: 2,
+		TileSizeX:  2,
+		TileSizeY:  1,
+		Tiles:      map[int]bool{1: true},
+	}
+}
+
+func TestImageRenderer(t *testing.T) {
+	f := newTestFont()
+	renderer := NewImageRenderer(4, 2)
+
+	renderer.DrawBackground(0, 0, 4, 2, concolor.RGB(255, 0, 0))
+	renderer.DrawChar(f, 0, 0, 0, concolor.RGB(0, 255, 0))
+	renderer.DrawChar(f, 1, 2, 0, concolor.RGB(0, 255, 0))
+
+	img := renderer.Image
+	assert.Equal(t, color.RGBA{G: 255, A: 255}, img.RGBAAt(0, 0))
+	assert.Equal(t, color.RGBA{R: 255, A: 255}, img.RGBAAt(1, 0))
+	assert.Equal(t, color.RGBA{R: 255, A: 255}, img.RGBAAt(0, 1))
+
+	// Colored tiles keep their own color.
+	assert.Equal(t, color.RGBA{B: 255, A: 255}, img.RGBAAt(2, 0))
+	assert.Equal(t, color.RGBA{B: 255, A: 255}, img.RGBAAt(3, 1))
+
+	// Drawing outside of the image is ignored.
+	renderer.DrawBackground(-2, -2, 8, 8, concolor.RGBA(0, 0, 0, 0))
+	renderer.DrawChar(f, 0, -1, -1, concolor.White)
+	assert.Equal(t, color.RGBA{G: 255, A: 255}, img.RGBAAt(0, 0))
+	assert.Equal(t, color.RGBA{B: 255, A: 255}, img.RGBAAt(3, 1))
+}
+
+func TestRenderImage(test *testing.T) {
+	con, err := New(3, 1, newTestFont(), "")
+	if !assert.NoError(test, err) {
+		return
+	}
+
+	sub, err := con.CreateSubConsole(2, 0, 1, 1)
+	if !assert.NoError(test, err) {
+		return
+	}
+
+	assert.NoError(test, con.Transform(0, 0, t.Char(0), t.Foreground(concolor.RGB(0, 255, 0)), t.Background(concolor.RGB(255, 0, 0))))
+	assert.NoError(test, con.Transform(1, 0, t.Char(1)))
+	assert.NoError(test, sub.Transform(0, 0, t.Char(-1), t.Background(concolor.RGB(255, 255, 0))))
+
+	img := con.RenderImage()
+	assert.Equal(test, image.Rect(0, 0, 6, 2), img.Bounds())
+	assert.Equal(test, color.RGBA{G: 255, A: 255}, img.RGBAAt(0, 0))
+	assert.Equal(test, color.RGBA{R: 255, A: 255}, img.RGBAAt(1, 1))
+	assert.Equal(test, color.RGBA{B: 255, A: 255}, img.RGBAAt(2, 0))
+	assert.Equal(test, color.RGBA{R: 255, G: 255, A: 255}, img.RGBAAt(5, 1))
+}
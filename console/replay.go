@@ -0,0 +1,433 @@
+package console
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// InputClock can be implemented by an input source that records or overrides the
+// elapsed time of a tick. The console passes the measured time of every tick to it
+// and uses the returned time for the components and the tick hook.
+type InputClock interface {
+	TimeElapsed(measured float64) float64
+}
+
+// InputFrame is the recorded input state of a single tick.
+type InputFrame struct {
+	CursorX int
+	CursorY int
+	WheelX  float64
+	WheelY  float64
+	Keys    []ebiten.Key
+	Buttons []ebiten.MouseButton
+	Chars   []rune
+	Elapsed float64
+}
+
+var inputReplayMagic = []byte("RMNINPUT")
+
+const inputReplayVersion = 1
+
+const (
+	frameCursor = 1 << iota
+	frameWheel
+	frameKeys
+	frameButtons
+	frameChars
+	frameElapsed
+)
+
+// InputRecorder is an input source that records the state of another input source
+// on every tick, so that it can be replayed later with an InputReplay.
+//
+//	recorder := console.NewInputRecorder(console.EbitenInput{})
+//	con.SetInput(recorder)
+//	...
+//	recorder.Save("session.rin")
+type InputRecorder struct {
+	Input
+
+	mtx    sync.Mutex
+	frames []InputFrame
+}
+
+// NewInputRecorder creates a recorder that passes the state of the source through.
+func NewInputRecorder(source Input) *InputRecorder {
+	return &InputRecorder{Input: source}
+}
+
+// Update updates the source and records its state.
+func (r *InputRecorder) Update() {
+	r.Input.Update()
+
+	frame := InputFrame{Chars: r.Input.InputChars()}
+	frame.CursorX, frame.CursorY = r.Input.CursorPosition()
+	frame.WheelX, frame.WheelY = r.Input.Wheel()
+
+	for key := ebiten.Key(0); key <= ebiten.KeyMax; key++ {
+		if r.Input.IsKeyPressed(key) {
+			frame.Keys = append(frame.Keys, key)
+		}
+	}
+
+	for _, button := range mouseButtons {
+		if r.Input.IsMouseButtonPressed(button) {
+			frame.Buttons = append(frame.Buttons, button)
+		}
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	r.frames = append(r.frames, frame)
+}
+
+// TimeElapsed records the elapsed time of the current tick.
+func (r *InputRecorder) TimeElapsed(measured float64) float64 {
+	if clock, ok := r.Input.(InputClock); ok {
+		measured = clock.TimeElapsed(measured)
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if len(r.frames) > 0 {
+		r.frames[len(r.frames)-1].Elapsed = measured
+	}
+	return measured
+}
+
+// Frames returns a copy of the recorded frames.
+func (r *InputRecorder) Frames() []InputFrame {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	return append([]InputFrame(nil), r.frames...)
+}
+
+// Reset removes all recorded frames.
+func (r *InputRecorder) Reset() {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	r.frames = nil
+}
+
+// Write writes the recorded frames in a compact binary format. Only the parts of
+// the state that changed since the previous frame are stored.
+func (r *InputRecorder) Write(w io.Writer) error {
+	return WriteInputFrames(w, r.Frames())
+}
+
+// Save writes the recorded frames to a file.
+func (r *InputRecorder) Save(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := r.Write(file); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// WriteInputFrames writes the frames in the compact binary format of the input recorder.
+func WriteInputFrames(w io.Writer, frames []InputFrame) error {
+	var buf bytes.Buffer
+	buf.Write(inputReplayMagic)
+	buf.WriteByte(inputReplayVersion)
+	writeUvarint(&buf, uint64(len(frames)))
+
+	var prev InputFrame
+	for i := range frames {
+		frame := frames[i]
+
+		var flags byte
+		if i == 0 || frame.CursorX != prev.CursorX || frame.CursorY != prev.CursorY {
+			flags |= frameCursor
+		}
+		if frame.WheelX != 0 || frame.WheelY != 0 {
+			flags |= frameWheel
+		}
+		if !equalKeys(frame.Keys, prev.Keys) {
+			flags |= frameKeys
+		}
+		if !equalButtons(frame.Buttons, prev.Buttons) {
+			flags |= frameButtons
+		}
+		if len(frame.Chars) > 0 {
+			flags |= frameChars
+		}
+		if frame.Elapsed != prev.Elapsed {
+			flags |= frameElapsed
+		}
+
+		buf.WriteByte(flags)
+		if flags&frameCursor != 0 {
+			writeVarint(&buf, int64(frame.CursorX))
+			writeVarint(&buf, int64(frame.CursorY))
+		}
+		if flags&frameWheel != 0 {
+			writeUvarint(&buf, math.Float64bits(frame.WheelX))
+			writeUvarint(&buf, math.Float64bits(frame.WheelY))
+		}
+		if flags&frameKeys != 0 {
+			writeUvarint(&buf, uint64(len(frame.Keys)))
+			for _, key := range frame.Keys {
+				writeUvarint(&buf, uint64(key))
+			}
+		}
+		if flags&frameButtons != 0 {
+			writeUvarint(&buf, uint64(len(frame.Buttons)))
+			for _, button := range frame.Buttons {
+				writeUvarint(&buf, uint64(button))
+			}
+		}
+		if flags&frameChars != 0 {
+			writeUvarint(&buf, uint64(len(frame.Chars)))
+			for _, char := range frame.Chars {
+				writeUvarint(&buf, uint64(char))
+			}
+		}
+		if flags&frameElapsed != 0 {
+			writeUvarint(&buf, math.Float64bits(frame.Elapsed))
+		}
+
+		prev = frame
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// ReadInputFrames reads frames that were written by an input recorder.
+func ReadInputFrames(r io.Reader) ([]InputFrame, error) {
+	reader := bufio.NewReader(r)
+
+	header := make([]byte, len(inputReplayMagic)+1)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(header[:len(inputReplayMagic)], inputReplayMagic) {
+		return nil, fmt.Errorf("not an input recording")
+	}
+	if header[len(inputReplayMagic)] != inputReplayVersion {
+		return nil, fmt.Errorf("unsupported input recording version %d", header[len(inputReplayMagic)])
+	}
+
+	count, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decoding errors are collected so that the format can be read without
+	// checking every single value.
+	var readErr error
+	readU := func() uint64 {
+		if readErr != nil {
+			return 0
+		}
+		var v uint64
+		v, readErr = binary.ReadUvarint(reader)
+		return v
+	}
+	readI := func() int {
+		if readErr != nil {
+			return 0
+		}
+		var v int64
+		v, readErr = binary.ReadVarint(reader)
+		return int(v)
+	}
+
+	var frames []InputFrame
+	var prev InputFrame
+	for i := uint64(0); i < count; i++ {
+		flags, err := reader.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("frame %d: %w", i, err)
+		}
+
+		frame := InputFrame{CursorX: prev.CursorX, CursorY: prev.CursorY, Keys: prev.Keys, Buttons: prev.Buttons, Elapsed: prev.Elapsed}
+		if flags&frameCursor != 0 {
+			frame.CursorX, frame.CursorY = readI(), readI()
+		}
+		if flags&frameWheel != 0 {
+			frame.WheelX, frame.WheelY = math.Float64frombits(readU()), math.Float64frombits(readU())
+		}
+		if flags&frameKeys != 0 {
+			frame.Keys = nil
+			for n := readU(); n > 0 && readErr == nil; n-- {
+				frame.Keys = append(frame.Keys, ebiten.Key(readU()))
+			}
+		}
+		if flags&frameButtons != 0 {
+			frame.Buttons = nil
+			for n := readU(); n > 0 && readErr == nil; n-- {
+				frame.Buttons = append(frame.Buttons, ebiten.MouseButton(readU()))
+			}
+		}
+		if flags&frameChars != 0 {
+			frame.Chars = nil
+			for n := readU(); n > 0 && readErr == nil; n-- {
+				frame.Chars = append(frame.Chars, rune(readU()))
+			}
+		}
+		if flags&frameElapsed != 0 {
+			frame.Elapsed = math.Float64frombits(readU())
+		}
+
+		if readErr != nil {
+			return nil, fmt.Errorf("frame %d: %w", i, readErr)
+		}
+
+		frames = append(frames, frame)
+		prev = frame
+	}
+
+	return frames, nil
+}
+
+// InputReplay is an input source that plays recorded frames back, one frame per tick.
+// Together with the recorded elapsed time this drives the components and the tick hook
+// exactly like in the recorded session. Without a window the console can be driven
+// headless by calling Update until the replay is done:
+//
+//	for !replay.Done() {
+//		con.Update()
+//	}
+type InputReplay struct {
+	*MemoryInput
+
+	mtx    sync.Mutex
+	frames []InputFrame
+	next   int
+}
+
+// NewInputReplay creates a replay of the frames.
+func NewInputReplay(frames []InputFrame) *InputReplay {
+	return &InputReplay{MemoryInput: NewMemoryInput(), frames: frames}
+}
+
+// ReadInputReplay reads a recording and creates a replay of it.
+func ReadInputReplay(r io.Reader) (*InputReplay, error) {
+	frames, err := ReadInputFrames(r)
+	if err != nil {
+		return nil, err
+	}
+	return NewInputReplay(frames), nil
+}
+
+// LoadInputReplay loads a recording from a file and creates a replay of it.
+func LoadInputReplay(path string) (*InputReplay, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ReadInputReplay(file)
+}
+
+// Update advances the replay to the next frame. After the last frame all keys
+// and buttons are released.
+func (r *InputReplay) Update() {
+	r.mtx.Lock()
+	var frame InputFrame
+	if r.next < len(r.frames) {
+		frame = r.frames[r.next]
+		r.next++
+	}
+	r.mtx.Unlock()
+
+	m := r.MemoryInput
+	m.mtx.Lock()
+	m.cursorX, m.cursorY = frame.CursorX, frame.CursorY
+	m.pendingWheelX, m.pendingWheelY = frame.WheelX, frame.WheelY
+	m.pendingChars = append([]rune(nil), frame.Chars...)
+	m.keys = map[ebiten.Key]bool{}
+	for _, key := range frame.Keys {
+		m.keys[key] = true
+	}
+	m.buttons = map[ebiten.MouseButton]bool{}
+	for _, button := range frame.Buttons {
+		m.buttons[button] = true
+	}
+	m.mtx.Unlock()
+
+	m.Update()
+}
+
+// TimeElapsed returns the recorded elapsed time of the current frame.
+func (r *InputReplay) TimeElapsed(measured float64) float64 {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if r.next == 0 || r.next > len(r.frames) {
+		return measured
+	}
+	return r.frames[r.next-1].Elapsed
+}
+
+// Done returns true if all frames have been played.
+func (r *InputReplay) Done() bool {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	return r.next >= len(r.frames)
+}
+
+// Rewind restarts the replay at the first frame. It must not be called while
+// the console is updating.
+func (r *InputReplay) Rewind() {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	r.next = 0
+	r.MemoryInput = NewMemoryInput()
+}
+
+func writeUvarint(buf *bytes.Buffer, v uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	buf.Write(tmp[:binary.PutUvarint(tmp[:], v)])
+}
+
+func writeVarint(buf *bytes.Buffer, v int64) {
+	var tmp [binary.MaxVarintLen64]byte
+	buf.Write(tmp[:binary.PutVarint(tmp[:], v)])
+}
+
+func equalKeys(a, b []ebiten.Key) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func equalButtons(a, b []ebiten.MouseButton) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+package console
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInputReplay(t *testing.T) {
+	in := NewMemoryInput()
+	recorder := NewInputRecorder(in)
+
+	tick := func(elapsed float64) {
+		recorder.Update()
+		recorder.TimeElapsed(elapsed)
+	}
+
+	in.SetCursor(10, 20)
+	in.PressKey(ebiten.KeyA)
+	tick(1.0 / 60)
+
+	in.TypeText("hé")
+	in.PressButton(ebiten.MouseButtonLeft)
+	tick(1.0 / 60)
+
+	in.ReleaseKey(ebiten.KeyA)
+	in.ScrollWheel(0, -1.5)
+	in.SetCursor(-3, 4)
+	tick(0.5)
+
+	var buf bytes.Buffer
+	if !assert.NoError(t, recorder.Write(&buf)) {
+		return
+	}
+
+	replay, err := ReadInputReplay(&buf)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, recorder.Frames(), replay.frames)
+
+	replay.Update()
+	assert.True(t, replay.IsKeyJustPressed(ebiten.KeyA))
+	assert.Equal(t, 1.0/60, replay.TimeElapsed(0))
+
+	replay.Update()
+	assert.True(t, replay.IsMouseButtonJustPressed(ebiten.MouseButtonLeft))
+	assert.Equal(t, "hé", string(replay.InputChars()))
+	assert.Equal(t, 2, replay.KeyPressDuration(ebiten.KeyA))
+
+	replay.Update()
+	x, y := replay.CursorPosition()
+	_, wheel := replay.Wheel()
+	assert.True(t, replay.IsKeyJustReleased(ebiten.KeyA))
+	assert.Equal(t, []int{-3, 4}, []int{x, y})
+	assert.Equal(t, -1.5, wheel)
+	assert.Equal(t, 0.5, replay.TimeElapsed(0))
+	assert.True(t, replay.Done())
+
+	_, err = ReadInputFrames(bytes.NewReader([]byte("RMNINPUT")))
+	assert.Error(t, err)
+}
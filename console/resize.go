@@ -0,0 +1,105 @@
+package console
+
+import (
+	"fmt"
+
+	"github.com/BigJk/ramen"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Resize changes the size of the console. Content that is inside both the old
+// and new size is kept. After resizing the parent resize hooks of all
+// sub-consoles are triggered, so that they can adjust to the new size.
+// Sub-consoles keep their position and size, so if the console shrinks they
+// are clipped the same way as sub-consoles that are moved partially outside.
+func (c *Console) Resize(width, height int) error {
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("console needs a size greater than zero")
+	}
+
+	c.mtx.Lock()
+	if width == c.Width && height == c.Height {
+		c.mtx.Unlock()
+		return nil
+	}
+
+	buf := make([][]ramen.Cell, width)
+	for x := range buf {
+		buf[x] = make([]ramen.Cell, height)
+		for y := range buf[x] {
+			if x < c.Width && y < c.Height {
+				buf[x][y] = c.buffer[x][y]
+			} else {
+				buf[x][y] = emptyCell
+			}
+		}
+	}
+
+	c.buffer = buf
+	c.Width = width
+	c.Height = height
+
+	c.dirty = make([]bool, width*height)
+	c.dirtyAll = true
+	if c.cache != nil {
+		c.cache.Dispose()
+		c.cache = nil
+	}
+
+	subConsoles := make([]*Console, len(c.SubConsoles))
+	copy(subConsoles, c.SubConsoles)
+	c.mtx.Unlock()
+
+	for i := range subConsoles {
+		if subConsoles[i].resizeHook == nil {
+			continue
+		}
+
+		if err := subConsoles[i].resizeHook(width, height); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetAutoResize enables or disables the auto resize mode. If enabled the window
+// can be resized and the console will change its size to fill the window
+// instead of stretching its content.
+func (c *Console) SetAutoResize(enabled bool) error {
+	if c.isSubConsole || c.isOffscreen {
+		return fmt.Errorf("only the main console can be auto resized")
+	}
+	c.autoResize = enabled
+	ebiten.SetWindowResizable(enabled)
+	return nil
+}
+
+// SetParentResizeHook will apply a hook that gets triggered after the parent of
+// the sub-console has been resized. This is a good place to re-anchor or resize
+// the sub-console.
+func (c *Console) SetParentResizeHook(hook func(parentWidth, parentHeight int) error) error {
+	if !c.isSubConsole {
+		return fmt.Errorf("only sub-consoles have a parent")
+	}
+	c.resizeHook = hook
+	return nil
+}
+
+func (c *Console) resizeToWindow(outsideWidth, outsideHeight int) {
+	scale := c.scale
+	if scale <= 0 {
+		scale = 1
+	}
+
+	width := int(float64(outsideWidth) / (float64(c.Font.TileWidth) * scale))
+	height := int(float64(outsideHeight) / (float64(c.Font.TileHeight) * scale))
+	if width < 1 {
+		width = 1
+	}
+	if height < 1 {
+		height = 1
+	}
+
+	_ = c.Resize(width, height)
+}
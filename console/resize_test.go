@@ -0,0 +1,100 @@
+package console
+
+import (
+	"image"
+	"image/color"
+	"sync"
+	"testing"
+
+	"github.com/BigJk/ramen/concolor"
+	"github.com/BigJk/ramen/t"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResizeClipsSubConsoles(test *testing.T) {
+	con, err := New(5, 5, newTestFont(), "")
+	if !assert.NoError(test, err) {
+		return
+	}
+
+	partial, err := con.CreateSubConsole(3, 3, 2, 2)
+	if !assert.NoError(test, err) {
+		return
+	}
+
+	outside, err := con.CreateSubConsole(4, 0, 1, 1)
+	if !assert.NoError(test, err) {
+		return
+	}
+
+	assert.NoError(test, partial.TransformAll(t.Char(-1), t.Background(concolor.RGB(255, 255, 0))))
+	assert.NoError(test, outside.TransformAll(t.Char(-1), t.Background(concolor.RGB(0, 255, 255))))
+
+	var hookWidth, hookHeight int
+	assert.NoError(test, partial.SetParentResizeHook(func(parentWidth, parentHeight int) error {
+		hookWidth, hookHeight = parentWidth, parentHeight
+		return nil
+	}))
+
+	assert.NoError(test, con.Resize(4, 4))
+	assert.Equal(test, 4, hookWidth)
+	assert.Equal(test, 4, hookHeight)
+
+	// The sub-consoles keep their size and are clipped by the parent.
+	assert.Equal(test, 2, partial.Width)
+	x, y := outside.Position()
+	assert.Equal(test, 4, x)
+	assert.Equal(test, 0, y)
+
+	img := con.RenderImage()
+	assert.Equal(test, image.Rect(0, 0, 8, 8), img.Bounds())
+	assert.Equal(test, color.RGBA{R: 255, G: 255, A: 255}, img.RGBAAt(6, 6))
+	assert.Equal(test, color.RGBA{R: 255, G: 255, A: 255}, img.RGBAAt(7, 7))
+	assert.Equal(test, color.RGBA{}, img.RGBAAt(7, 0))
+
+	// Moving them back inside shows them again.
+	assert.NoError(test, outside.SetPosition(3, 0))
+	img = con.RenderImage()
+	assert.Equal(test, color.RGBA{G: 255, B: 255, A: 255}, img.RGBAAt(7, 0))
+}
+
+func TestConcurrentResize(test *testing.T) {
+	con, err := New(4, 4, newTestFont(), "")
+	if !assert.NoError(test, err) {
+		return
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	// The helpers that cover the whole console have to read the size together
+	// with the cells, which is meant to be run with the race detector.
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+				_ = con.Resize(4+i%3, 4+i%2)
+			}
+		}
+	}()
+
+	for i := 0; i < 200; i++ {
+		assert.NoError(test, con.TransformAll(t.Char('a')))
+		assert.NoError(test, con.Scroll(1, 1, emptyCell))
+
+		cells := con.Cells()
+		for x := range cells {
+			assert.Len(test, cells[x], len(cells[0]))
+		}
+
+		img := con.RenderImage()
+		assert.Equal(test, 0, img.Bounds().Dx()%2)
+	}
+
+	close(done)
+	wg.Wait()
+}
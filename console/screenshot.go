@@ -0,0 +1,29 @@
+package console
+
+import (
+	"image"
+	"image/png"
+	"os"
+)
+
+// Screenshot renders the console including all sub-consoles and components into
+// an image. Hooks are not triggered, so content that is drawn directly onto the
+// ebiten screen is not included.
+func (c *Console) Screenshot() image.Image {
+	return c.RenderImage()
+}
+
+// SavePNG saves a screenshot of the console as png file.
+func (c *Console) SavePNG(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := png.Encode(file, c.Screenshot()); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
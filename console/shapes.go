@@ -0,0 +1,371 @@
+package console
+
+import (
+	"fmt"
+
+	"github.com/BigJk/ramen/t"
+)
+
+// FrameStyle specifies the chars that are used to draw a frame.
+type FrameStyle struct {
+	Horizontal  int
+	Vertical    int
+	TopLeft     int
+	TopRight    int
+	BottomLeft  int
+	BottomRight int
+}
+
+var (
+	// FrameSingle draws a frame with single lines. The chars are taken from Code Page 437.
+	FrameSingle = FrameStyle{Horizontal: 196, Vertical: 179, TopLeft: 218, TopRight: 191, BottomLeft: 192, BottomRight: 217}
+	// FrameDouble draws a frame with double lines. The chars are taken from Code Page 437.
+	FrameDouble = FrameStyle{Horizontal: 205, Vertical: 186, TopLeft: 201, TopRight: 187, BottomLeft: 200, BottomRight: 188}
+	// FrameThick draws a frame with full blocks. The chars are taken from Code Page 437.
+	FrameThick = FrameStyle{Horizontal: 219, Vertical: 219, TopLeft: 219, TopRight: 219, BottomLeft: 219, BottomRight: 219}
+)
+
+// DrawRect applies the transformers to the outline of the given area.
+func (c *Console) DrawRect(x, y, width, height int, transformer ...t.Transformer) error {
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	area := t.Area{X: x, Y: y, Width: width, Height: height}
+	for px := x; px < x+width; px++ {
+		if err := c.transformClipped(px, y, area, transformer...); err != nil {
+			return err
+		}
+		if height > 1 {
+			if err := c.transformClipped(px, y+height-1, area, transformer...); err != nil {
+				return err
+			}
+		}
+	}
+
+	for py := y + 1; py < y+height-1; py++ {
+		if err := c.transformClipped(x, py, area, transformer...); err != nil {
+			return err
+		}
+		if width > 1 {
+			if err := c.transformClipped(x+width-1, py, area, transformer...); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// FillRect applies the transformers to all cells in the given area. In contrast to
+// TransformArea parts of the area that are outside of the console are ignored.
+func (c *Console) FillRect(x, y, width, height int, transformer ...t.Transformer) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	area := t.Area{X: x, Y: y, Width: width, Height: height}
+	for px := x; px < x+width; px++ {
+		for py := y; py < y+height; py++ {
+			if err := c.transformClipped(px, py, area, transformer...); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// DrawFrame draws a frame with the chars of the given style around the area. If the
+// title is not empty it will be printed centered in the top line of the frame. The
+// transformers are applied to the frame and the title. Like DrawRect nothing is drawn
+// for an empty area, and frames that are only one cell wide or high are drawn with
+// the corners and lines that fit.
+func (c *Console) DrawFrame(x, y, width, height int, style FrameStyle, title string, transformer ...t.Transformer) error {
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+
+	with := func(char int) []t.Transformer {
+		return append(append([]t.Transformer{}, transformer...), t.Char(char))
+	}
+
+	c.mtx.Lock()
+	area := t.Area{X: x, Y: y, Width: width, Height: height}
+	for px := x; px < x+width; px++ {
+		for py := y; py < y+height; py++ {
+			var char int
+			switch {
+			case px == x && py == y:
+				char = style.TopLeft
+			case px == x+width-1 && py == y:
+				char = style.TopRight
+			case px == x && py == y+height-1:
+				char = style.BottomLeft
+			case px == x+width-1 && py == y+height-1:
+				char = style.BottomRight
+			case py == y || py == y+height-1:
+				char = style.Horizontal
+			case px == x || px == x+width-1:
+				char = style.Vertical
+			default:
+				continue
+			}
+
+			if err := c.transformClipped(px, py, area, with(char)...); err != nil {
+				c.mtx.Unlock()
+				return err
+			}
+		}
+	}
+	c.mtx.Unlock()
+
+	if len(title) > 0 && width > 4 {
+		c.PrintWith(x+2, y, width-4, 1, PrintOptions{Align: AlignCenter}, title, transformer...)
+	}
+
+	return nil
+}
+
+// DrawLine applies the transformers to all cells on the line between the two points.
+func (c *Console) DrawLine(x0, y0, x1, y1 int, transformer ...t.Transformer) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	area := boundingArea(x0, y0, x1, y1)
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := sign(x1-x0), sign(y1-y0)
+	e := dx + dy
+
+	for {
+		if err := c.transformClipped(x0, y0, area, transformer...); err != nil {
+			return err
+		}
+
+		if x0 == x1 && y0 == y1 {
+			return nil
+		}
+
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+// DrawCircle applies the transformers to the outline of a circle.
+func (c *Console) DrawCircle(cx, cy, radius int, transformer ...t.Transformer) error {
+	return c.DrawEllipse(cx, cy, radius, radius, transformer...)
+}
+
+// FillCircle applies the transformers to all cells inside a circle.
+func (c *Console) FillCircle(cx, cy, radius int, transformer ...t.Transformer) error {
+	return c.FillEllipse(cx, cy, radius, radius, transformer...)
+}
+
+// DrawEllipse applies the transformers to the outline of an ellipse with the
+// radii rx and ry.
+func (c *Console) DrawEllipse(cx, cy, rx, ry int, transformer ...t.Transformer) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	area := boundingArea(cx-rx, cy-ry, cx+rx, cy+ry)
+	seen := map[[2]int]bool{}
+	return ellipse(rx, ry, func(x, y int) error {
+		for _, p := range [][2]int{{cx + x, cy + y}, {cx - x, cy + y}, {cx + x, cy - y}, {cx - x, cy - y}} {
+			if seen[p] {
+				continue
+			}
+			seen[p] = true
+
+			if err := c.transformClipped(p[0], p[1], area, transformer...); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FillEllipse applies the transformers to all cells inside an ellipse with the
+// radii rx and ry.
+func (c *Console) FillEllipse(cx, cy, rx, ry int, transformer ...t.Transformer) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	area := boundingArea(cx-rx, cy-ry, cx+rx, cy+ry)
+
+	// Collect the widest span of every row first, so that no cell is
+	// transformed twice.
+	spans := map[int]int{}
+	_ = ellipse(rx, ry, func(x, y int) error {
+		if widest, ok := spans[y]; !ok || x > widest {
+			spans[y] = x
+		}
+		return nil
+	})
+
+	for y, x := range spans {
+		for px := cx - x; px <= cx+x; px++ {
+			if err := c.transformClipped(px, cy+y, area, transformer...); err != nil {
+				return err
+			}
+			if y == 0 {
+				continue
+			}
+			if err := c.transformClipped(px, cy-y, area, transformer...); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// FloodFill applies the transformers to the cell at the given position and all
+// connected cells that are equal to it.
+func (c *Console) FloodFill(x, y int, transformer ...t.Transformer) error {
+	if len(transformer) == 0 {
+		return fmt.Errorf("no transformer given")
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if err := c.checkOutOfBounds(x, y); err != nil {
+		return err
+	}
+
+	area := t.Area{Width: c.Width, Height: c.Height}
+	target := c.buffer[x][y]
+	visited := make([]bool, c.Width*c.Height)
+	stack := [][2]int{{x, y}}
+
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if c.checkOutOfBounds(p[0], p[1]) != nil || visited[p[0]*c.Height+p[1]] || c.buffer[p[0]][p[1]] != target {
+			continue
+		}
+		visited[p[0]*c.Height+p[1]] = true
+
+		if err := c.transformCell(p[0], p[1], area, transformer...); err != nil {
+			return err
+		}
+
+		stack = append(stack, [2]int{p[0] + 1, p[1]}, [2]int{p[0] - 1, p[1]}, [2]int{p[0], p[1] + 1}, [2]int{p[0], p[1] - 1})
+	}
+
+	return nil
+}
+
+// transformClipped applies the transformers to the cell if it is inside the console.
+// The write lock of the console needs to be held.
+func (c *Console) transformClipped(x, y int, area t.Area, transformer ...t.Transformer) error {
+	if c.checkOutOfBounds(x, y) != nil {
+		return nil
+	}
+
+	if len(transformer) == 0 {
+		c.setCell(x, y, emptyCell)
+		return nil
+	}
+
+	return c.transformCell(x, y, area, transformer...)
+}
+
+// ellipse calls plot for every point of the first quadrant of an ellipse
+// with the radii rx and ry using the midpoint algorithm.
+func ellipse(rx, ry int, plot func(x, y int) error) error {
+	if rx < 0 || ry < 0 {
+		return nil
+	}
+
+	if rx == 0 || ry == 0 {
+		for x := 0; x <= rx; x++ {
+			for y := 0; y <= ry; y++ {
+				if err := plot(x, y); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	rx2, ry2 := rx*rx, ry*ry
+	x, y := 0, ry
+	px, py := 0, 2*rx2*y
+
+	// Region 1
+	p := ry2 - rx2*ry + rx2/4
+	for px < py {
+		if err := plot(x, y); err != nil {
+			return err
+		}
+
+		x++
+		px += 2 * ry2
+		if p < 0 {
+			p += ry2 + px
+		} else {
+			y--
+			py -= 2 * rx2
+			p += ry2 + px - py
+		}
+	}
+
+	// Region 2
+	p = ry2*(2*x+1)*(2*x+1)/4 + rx2*(y-1)*(y-1) - rx2*ry2
+	for y >= 0 {
+		if err := plot(x, y); err != nil {
+			return err
+		}
+
+		y--
+		py -= 2 * rx2
+		if p > 0 {
+			p += rx2 - py
+		} else {
+			x++
+			px += 2 * ry2
+			p += rx2 - py + px
+		}
+	}
+
+	return nil
+}
+
+// boundingArea returns the area that contains both points.
+func boundingArea(x0, y0, x1, y1 int) t.Area {
+	if x0 > x1 {
+		x0, x1 = x1, x0
+	}
+	if y0 > y1 {
+		y0, y1 = y1, y0
+	}
+	return t.Area{X: x0, Y: y0, Width: x1 - x0 + 1, Height: y1 - y0 + 1}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func sign(v int) int {
+	switch {
+	case v < 0:
+		return -1
+	case v > 0:
+		return 1
+	}
+	return 0
+}
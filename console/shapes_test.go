@@ -0,0 +1,109 @@
+package console
+
+import (
+	"testing"
+
+	"github.com/BigJk/ramen/t"
+	"github.com/stretchr/testify/assert"
+)
+
+// charRows returns the chars of the console as one string per row. Empty
+// cells are returned as spaces.
+func charRows(test *testing.T, con *Console) []string {
+	rows := make([]string, con.Height)
+	for y := range rows {
+		row := make([]rune, con.Width)
+		for x := range row {
+			cell, err := con.Get(x, y)
+			assert.NoError(test, err)
+
+			row[x] = rune(cell.Char)
+			if row[x] == 0 {
+				row[x] = ' '
+			}
+		}
+		rows[y] = string(row)
+	}
+	return rows
+}
+
+var asciiFrame = FrameStyle{Horizontal: '-', Vertical: '|', TopLeft: '+', TopRight: '+', BottomLeft: '+', BottomRight: '+'}
+
+func TestShapes(test *testing.T) {
+	cases := []struct {
+		name string
+		draw func(con *Console) error
+		rows []string
+	}{
+		{"DrawRect", func(con *Console) error {
+			return con.DrawRect(1, 1, 4, 3, t.Char('#'))
+		}, []string{"      ", " #### ", " #  # ", " #### ", "      "}},
+		{"DrawRectLine", func(con *Console) error {
+			return con.DrawRect(0, 2, 6, 1, t.Char('#'))
+		}, []string{"      ", "      ", "######", "      ", "      "}},
+		{"DrawRectEmpty", func(con *Console) error {
+			return con.DrawRect(1, 1, 0, 3, t.Char('#'))
+		}, []string{"      ", "      ", "      ", "      ", "      "}},
+		{"DrawRectClipped", func(con *Console) error {
+			return con.DrawRect(-1, 3, 3, 3, t.Char('#'))
+		}, []string{"      ", "      ", "      ", "##    ", " #    "}},
+		{"FillRectClipped", func(con *Console) error {
+			return con.FillRect(4, 3, 5, 5, t.Char('#'))
+		}, []string{"      ", "      ", "      ", "    ##", "    ##"}},
+		{"DrawFrame", func(con *Console) error {
+			return con.DrawFrame(0, 0, 6, 4, asciiFrame, "ab")
+		}, []string{"+-ab-+", "|    |", "|    |", "+----+", "      "}},
+		{"DrawFrameSingleRow", func(con *Console) error {
+			return con.DrawFrame(0, 1, 3, 1, asciiFrame, "")
+		}, []string{"      ", "+-+   ", "      ", "      ", "      "}},
+		{"DrawFrameSingleColumn", func(con *Console) error {
+			return con.DrawFrame(1, 0, 1, 3, asciiFrame, "")
+		}, []string{" +    ", " |    ", " +    ", "      ", "      "}},
+		{"DrawFrameEmpty", func(con *Console) error {
+			return con.DrawFrame(0, 0, 0, 0, asciiFrame, "title")
+		}, []string{"      ", "      ", "      ", "      ", "      "}},
+		{"DrawLine", func(con *Console) error {
+			return con.DrawLine(0, 0, 5, 2, t.Char('#'))
+		}, []string{"##    ", "  ##  ", "    ##", "      ", "      "}},
+		{"DrawLineReversed", func(con *Console) error {
+			return con.DrawLine(2, 4, 2, 1, t.Char('#'))
+		}, []string{"      ", "  #   ", "  #   ", "  #   ", "  #   "}},
+		{"DrawCircle", func(con *Console) error {
+			return con.DrawCircle(2, 2, 2, t.Char('#'))
+		}, []string{" ###  ", "#   # ", "#   # ", "#   # ", " ###  "}},
+		{"FillCircle", func(con *Console) error {
+			return con.FillCircle(2, 2, 1, t.Char('#'))
+		}, []string{"      ", "  #   ", " ###  ", "  #   ", "      "}},
+		{"FillEllipse", func(con *Console) error {
+			return con.FillEllipse(3, 2, 2, 1, t.Char('#'))
+		}, []string{"      ", "  ### ", " #####", "  ### ", "      "}},
+		{"FloodFill", func(con *Console) error {
+			if err := con.DrawRect(0, 0, 4, 4, t.Char('#')); err != nil {
+				return err
+			}
+			return con.FloodFill(1, 1, t.Char('.'))
+		}, []string{"####  ", "#..#  ", "#..#  ", "####  ", "      "}},
+	}
+
+	for _, c := range cases {
+		test.Run(c.name, func(test *testing.T) {
+			con, err := New(6, 5, newTestFont(), "")
+			if !assert.NoError(test, err) {
+				return
+			}
+
+			assert.NoError(test, c.draw(con))
+			assert.Equal(test, c.rows, charRows(test, con))
+		})
+	}
+}
+
+func TestFloodFillErrors(test *testing.T) {
+	con, err := New(6, 5, newTestFont(), "")
+	if !assert.NoError(test, err) {
+		return
+	}
+
+	assert.Error(test, con.FloodFill(0, 0))
+	assert.Error(test, con.FloodFill(6, 0, t.Char('#')))
+}
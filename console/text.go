@@ -1,23 +1,189 @@
 package console
 
 import (
-	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/BigJk/ramen/concolor"
-
 	"github.com/BigJk/ramen/t"
 )
 
-var colorSectionRegex = regexp.MustCompile("\\[\\[(([bf]):(#[0-9a-zA-Z]+))(\\|(([bf]):(#[0-9a-zA-Z]+)))?\\]\\]")
+// Span represents a section of a text that shares the same style.
+type Span struct {
+	Text       string
+	Foreground *concolor.Color
+	Background *concolor.Color
+
+	// If IsGlyph is true the span doesn't contain text but a single char of
+	// the font that is drawn as is, without mapping it through the charmap.
+	IsGlyph bool
+	Glyph   int
+}
+
+// Transformer returns the transformers that apply the style of the span.
+func (s Span) Transformer() []t.Transformer {
+	var trans []t.Transformer
+	if s.Foreground != nil {
+		trans = append(trans, t.Foreground(*s.Foreground))
+	}
+	if s.Background != nil {
+		trans = append(trans, t.Background(*s.Background))
+	}
+	return trans
+}
+
+// Spans represents a text that was split into styled sections.
+type Spans []Span
+
+// Text returns the text of all spans without any markup. Glyphs are
+// represented by the rune with the value of their char.
+func (s Spans) Text() string {
+	var sb strings.Builder
+	for i := range s {
+		if s[i].IsGlyph {
+			sb.WriteRune(rune(s[i].Glyph))
+		} else {
+			sb.WriteString(s[i].Text)
+		}
+	}
+	return sb.String()
+}
+
+// markupStyle is a entry of the style stack while parsing markup.
+type markupStyle struct {
+	foreground *concolor.Color
+	background *concolor.Color
+}
+
+// ParseMarkup parses the inlined markup of a text and returns the styled spans.
+// The following tags are supported:
+//
+//	[[f:red]]         sets the foreground color
+//	[[b:#00ff0080]]   sets the background color
+//	[[f:red|b:blue]]  sets multiple colors at once
+//	[[/]]             restores the style before the last opened tag
+//	[[reset]]         restores the default style
+//	[[c:219]]         inserts the char 219 of the font
+//	\[[               inserts a literal "[["
+//
+// Colors are parsed with concolor.Parse, so all CSS named colors as well as the hex
+// and rgb() notations are supported. Tags that can't be parsed are kept as text.
+func ParseMarkup(text string) Spans {
+	var spans Spans
+	var stack []markupStyle
+	var current markupStyle
+	var sb strings.Builder
+
+	flush := func() {
+		if sb.Len() == 0 {
+			return
+		}
+		spans = append(spans, Span{Text: sb.String(), Foreground: current.foreground, Background: current.background})
+		sb.Reset()
+	}
+
+	for len(text) > 0 {
+		start := strings.Index(text, "[[")
+		if start < 0 {
+			sb.WriteString(text)
+			break
+		}
+
+		if start > 0 && text[start-1] == '\\' {
+			sb.WriteString(text[:start-1])
+			sb.WriteString("[[")
+			text = text[start+2:]
+			continue
+		}
+
+		end := strings.Index(text[start+2:], "]]")
+		if end < 0 {
+			sb.WriteString(text)
+			break
+		}
+		end += start + 2
+
+		sb.WriteString(text[:start])
+		tag := text[start+2 : end]
+
+		// Tags that can't be parsed only keep their opening brackets as text, so
+		// a valid tag inside of them still applies.
+		invalid := text[start+2:]
+		text = text[end+2:]
+
+		switch {
+		case tag == "/":
+			if len(stack) > 0 {
+				flush()
+				current = stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+			}
+		case tag == "reset":
+			flush()
+			stack = stack[:0]
+			current = markupStyle{}
+		case strings.HasPrefix(tag, "c:"):
+			glyph, err := strconv.ParseInt(tag[2:], 0, 32)
+			if err != nil || glyph < 0 {
+				sb.WriteString("[[")
+				text = invalid
+				continue
+			}
+			flush()
+			spans = append(spans, Span{Foreground: current.foreground, Background: current.background, IsGlyph: true, Glyph: int(glyph)})
+		default:
+			style, ok := parseMarkupStyle(tag, current)
+			if !ok {
+				sb.WriteString("[[")
+				text = invalid
+				continue
+			}
+			flush()
+			stack = append(stack, current)
+			current = style
+		}
+	}
+	flush()
+
+	return spans
+}
+
+// parseMarkupStyle parses a tag like "f:red|b:#000" and applies it on top of the
+// current style.
+func parseMarkupStyle(tag string, current markupStyle) (markupStyle, bool) {
+	for _, attr := range strings.Split(tag, "|") {
+		if len(attr) < 3 || attr[1] != ':' {
+			return current, false
+		}
+
+		col, err := concolor.Parse(attr[2:])
+		if err != nil {
+			return current, false
+		}
+
+		switch attr[0] {
+		case 'f':
+			current.foreground = col.P()
+		case 'b':
+			current.background = col.P()
+		default:
+			return current, false
+		}
+	}
+	return current, true
+}
 
 // ColorSection represents a colorized section in a text.
+//
+// Deprecated: Use ParseMarkup and Spans instead.
 type ColorSection struct {
 	Index       int
 	Transformer []t.Transformer
 }
 
 // ColorSections represents a slice of color sections.
+//
+// Deprecated: Use ParseMarkup and Spans instead.
 type ColorSections []*ColorSection
 
 // GetCurrent gets the current color section for the given index in a string.
@@ -42,43 +208,21 @@ func (cs ColorSections) GetCurrentTransformer(index int) []t.Transformer {
 
 // ParseColoredText parses the coloring annotations in a string and returns the cleaned string
 // and the parsed color sections.
+//
+// Deprecated: Use ParseMarkup instead.
 func ParseColoredText(text string) (string, ColorSections) {
-	var results []*ColorSection
-	for {
-		match := colorSectionRegex.FindStringSubmatch(text)
-		if len(match) <= 1 {
-			break
-		}
+	spans := ParseMarkup(text)
 
-		var cs ColorSection
-		cs.Index = strings.Index(text, match[0])
-		text = strings.Replace(text, match[0], "", 1)
-
-		if len(match[4]) == 0 {
-			switch match[2] {
-			case "f":
-				cs.Transformer = append(cs.Transformer, t.Foreground(concolor.MustHex(match[3])))
-			case "b":
-				cs.Transformer = append(cs.Transformer, t.Background(concolor.MustHex(match[3])))
-			}
-		} else if len(match) == 8 {
-			switch match[2] {
-			case "f":
-				cs.Transformer = append(cs.Transformer, t.Foreground(concolor.MustHex(match[3])))
-			case "b":
-				cs.Transformer = append(cs.Transformer, t.Background(concolor.MustHex(match[3])))
-			}
-
-			switch match[6] {
-			case "f":
-				cs.Transformer = append(cs.Transformer, t.Foreground(concolor.MustHex(match[7])))
-			case "b":
-				cs.Transformer = append(cs.Transformer, t.Background(concolor.MustHex(match[7])))
-			}
+	var sb strings.Builder
+	var results ColorSections
+	for i := range spans {
+		results = append(results, &ColorSection{Index: sb.Len(), Transformer: spans[i].Transformer()})
+		if spans[i].IsGlyph {
+			sb.WriteRune(rune(spans[i].Glyph))
+		} else {
+			sb.WriteString(spans[i].Text)
 		}
-
-		results = append(results, &cs)
 	}
 
-	return text, results
+	return sb.String(), results
 }
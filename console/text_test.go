@@ -0,0 +1,66 @@
+package console
+
+import (
+	"testing"
+
+	"github.com/BigJk/ramen/concolor"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMarkup(t *testing.T) {
+	red := concolor.MustParse("red").P()
+	blue := concolor.MustParse("blue").P()
+	black := concolor.MustParse("#000").P()
+
+	cases := []struct {
+		name  string
+		text  string
+		spans Spans
+	}{
+		{"Empty", "", nil},
+		{"Plain", "hello", Spans{{Text: "hello"}}},
+		{"Foreground", "a[[f:red]]b", Spans{{Text: "a"}, {Text: "b", Foreground: red}}},
+		{"Multiple", "[[f:red|b:#000]]a", Spans{{Text: "a", Foreground: red, Background: black}}},
+		{"Stack", "[[f:red]]a[[b:blue]]b[[/]]c[[/]]d", Spans{
+			{Text: "a", Foreground: red},
+			{Text: "b", Foreground: red, Background: blue},
+			{Text: "c", Foreground: red},
+			{Text: "d"},
+		}},
+		{"Override", "[[f:red]]a[[f:blue]]b[[/]]c", Spans{
+			{Text: "a", Foreground: red},
+			{Text: "b", Foreground: blue},
+			{Text: "c", Foreground: red},
+		}},
+		{"Reset", "[[f:red]][[b:blue]]a[[reset]]b[[/]]c", Spans{
+			{Text: "a", Foreground: red, Background: blue},
+			{Text: "bc"},
+		}},
+		{"UnbalancedClose", "[[/]]a[[/]]", Spans{{Text: "a"}}},
+		{"EmptyTagsProduceNoSpans", "[[f:red]][[/]]a", Spans{{Text: "a"}}},
+		{"Escape", `a\[[f:red]]b`, Spans{{Text: "a[[f:red]]b"}}},
+		{"Glyph", "a[[c:219]]b", Spans{{Text: "a"}, {IsGlyph: true, Glyph: 219}, {Text: "b"}}},
+		{"GlyphHex", "[[c:0x10]]", Spans{{IsGlyph: true, Glyph: 16}}},
+		{"StyledGlyph", "[[f:red]][[c:1]]", Spans{{Foreground: red, IsGlyph: true, Glyph: 1}}},
+		{"InvalidGlyph", "[[c:x]]a", Spans{{Text: "[[c:x]]a"}}},
+		{"NegativeGlyph", "[[c:-1]]", Spans{{Text: "[[c:-1]]"}}},
+		{"UnknownAttribute", "[[x:red]]a", Spans{{Text: "[[x:red]]a"}}},
+		{"UnknownColor", "[[f:nope]]a", Spans{{Text: "[[f:nope]]a"}}},
+		{"PartlyInvalid", "[[f:red|b:nope]]a", Spans{{Text: "[[f:red|b:nope]]a"}}},
+		{"EmptyTag", "[[]]a", Spans{{Text: "[[]]a"}}},
+		{"TagInsideInvalidTag", "x [[bad [[f:red]]y", Spans{{Text: "x [[bad "}, {Text: "y", Foreground: red}}},
+		{"Unclosed", "a[[f:red", Spans{{Text: "a[[f:red"}}},
+		{"UnclosedAfterTag", "[[f:red]]a[[b:blue", Spans{{Text: "a[[b:blue", Foreground: red}}},
+		{"Newline", "[[f:red]]a\nb", Spans{{Text: "a\nb", Foreground: red}}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.spans, ParseMarkup(c.text))
+		})
+	}
+}
+
+func TestSpansText(t *testing.T) {
+	assert.Equal(t, "a[[b\x01c", ParseMarkup(`[[f:red]]a\[[b[[c:1]][[/]]c`).Text())
+}
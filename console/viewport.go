@@ -0,0 +1,173 @@
+package console
+
+import (
+	"fmt"
+)
+
+// CreateViewport creates a new sub-console that shows a window of the source console.
+// The source console can be bigger than the viewport, which makes it possible to
+// scroll over a big map by moving the camera of the viewport. Usually the source
+// is created with NewOffscreen.
+func (c *Console) CreateViewport(x, y, width, height int, source *Console) (*Console, error) {
+	if source == nil {
+		return nil, fmt.Errorf("viewport needs a source console")
+	}
+
+	sub, err := c.CreateSubConsole(x, y, width, height)
+	if err != nil {
+		return nil, err
+	}
+
+	sub.mtx.Lock()
+	sub.view = source
+	sub.mtx.Unlock()
+
+	return sub, nil
+}
+
+// IsViewport returns true if the console is a viewport onto another console.
+func (c *Console) IsViewport() bool {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.view != nil
+}
+
+// SetCamera moves the upper left corner of the viewport to the given position in the
+// source console. The camera will be clamped so that the viewport stays inside the source.
+func (c *Console) SetCamera(x, y int) error {
+	return c.moveCamera(func(width, height int) (int, int) {
+		return x, y
+	})
+}
+
+// CenterOn moves the camera so that the given position in the source console is in the
+// center of the viewport. The camera will be clamped so that the viewport stays inside
+// the source.
+func (c *Console) CenterOn(x, y int) error {
+	return c.moveCamera(func(width, height int) (int, int) {
+		return x - width/2, y - height/2
+	})
+}
+
+// moveCamera sets the camera to the position returned by target, which gets the
+// current size of the viewport.
+func (c *Console) moveCamera(target func(width, height int) (int, int)) error {
+	c.mtx.RLock()
+	view := c.view
+	c.mtx.RUnlock()
+
+	if view == nil {
+		return fmt.Errorf("console is not a viewport")
+	}
+
+	viewWidth, viewHeight := view.size()
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	x, y := target(c.Width, c.Height)
+	c.cameraX, c.cameraY = c.clampCamera(x, y, viewWidth, viewHeight)
+	return nil
+}
+
+// Camera returns the position of the upper left corner of the viewport in the source console.
+func (c *Console) Camera() (int, int) {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.cameraX, c.cameraY
+}
+
+// ToWorld translates a position in the viewport to the position in the source console.
+func (c *Console) ToWorld(x, y int) (int, int) {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return x + c.cameraX, y + c.cameraY
+}
+
+// WorldMousePosition returns the cell of the source console that the mouse cursor is
+// currently over. If it returns (-1, -1) the mouse cursor is currently not in the
+// viewport or the console is not a viewport.
+func (c *Console) WorldMousePosition() (int, int) {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.worldX, c.worldY
+}
+
+// viewSize returns the size of the source console or 0, 0 if the console is not a
+// viewport. The lock of the console must not be held.
+func (c *Console) viewSize() (int, int) {
+	c.mtx.RLock()
+	view := c.view
+	c.mtx.RUnlock()
+
+	if view == nil {
+		return 0, 0
+	}
+	return view.size()
+}
+
+// clampCamera keeps the camera inside a source console of the given size. The lock
+// of the console needs to be held.
+func (c *Console) clampCamera(x, y, viewWidth, viewHeight int) (int, int) {
+	if x > viewWidth-c.Width {
+		x = viewWidth - c.Width
+	}
+	if y > viewHeight-c.Height {
+		y = viewHeight - c.Height
+	}
+	if x < 0 {
+		x = 0
+	}
+	if y < 0 {
+		y = 0
+	}
+	return x, y
+}
+
+// updateWorldMousePosition translates the mouse position into the source console of
+// the given size. The lock of the console needs to be held.
+func (c *Console) updateWorldMousePosition(viewWidth, viewHeight int) {
+	if c.view == nil || c.mouseX < 0 || c.mouseY < 0 {
+		c.worldX, c.worldY = -1, -1
+		return
+	}
+
+	c.worldX, c.worldY = c.mouseX+c.cameraX, c.mouseY+c.cameraY
+	if c.worldX >= viewWidth || c.worldY >= viewHeight {
+		c.worldX, c.worldY = -1, -1
+	}
+}
+
+// syncView copies the visible window of the source console into the buffer.
+func (c *Console) syncView() {
+	c.mtx.RLock()
+	view := c.view
+	c.mtx.RUnlock()
+
+	if view == nil {
+		return
+	}
+
+	view.mtx.RLock()
+	defer view.mtx.RUnlock()
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.cameraX, c.cameraY = c.clampCamera(c.cameraX, c.cameraY, view.Width, view.Height)
+
+	for x := range c.buffer {
+		for y := range c.buffer[x] {
+			sx, sy := x+c.cameraX, y+c.cameraY
+			if sx < view.Width && sy < view.Height {
+				c.setCell(x, y, view.buffer[sx][sy])
+			} else {
+				c.setCell(x, y, emptyCell)
+			}
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package console
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestViewport(test *testing.T, sourceWidth, sourceHeight int) (*Console, *Console, *Console) {
+	con, err := New(10, 10, newTestFont(), "")
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	source, err := NewOffscreen(sourceWidth, sourceHeight, newTestFont())
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	view, err := con.CreateViewport(2, 2, 6, 4, source)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	return con, source, view
+}
+
+func TestViewportCamera(test *testing.T) {
+	cases := []struct {
+		name          string
+		move          func(view *Console) error
+		x, y          int
+		width, height int
+	}{
+		{"set inside", func(view *Console) error { return view.SetCamera(3, 2) }, 3, 2, 20, 10},
+		{"set before start", func(view *Console) error { return view.SetCamera(-5, -1) }, 0, 0, 20, 10},
+		{"set after end", func(view *Console) error { return view.SetCamera(100, 100) }, 14, 6, 20, 10},
+		{"center inside", func(view *Console) error { return view.CenterOn(10, 5) }, 7, 3, 20, 10},
+		{"center on start", func(view *Console) error { return view.CenterOn(0, 0) }, 0, 0, 20, 10},
+		{"center on end", func(view *Console) error { return view.CenterOn(19, 9) }, 14, 6, 20, 10},
+		{"source smaller than viewport", func(view *Console) error { return view.SetCamera(2, 2) }, 0, 0, 4, 2},
+	}
+
+	for _, c := range cases {
+		test.Run(c.name, func(test *testing.T) {
+			_, _, view := newTestViewport(test, c.width, c.height)
+
+			assert.NoError(test, c.move(view))
+			x, y := view.Camera()
+			assert.Equal(test, c.x, x)
+			assert.Equal(test, c.y, y)
+		})
+	}
+
+	con, err := New(2, 2, newTestFont(), "")
+	if assert.NoError(test, err) {
+		assert.Error(test, con.SetCamera(0, 0))
+		assert.Error(test, con.CenterOn(0, 0))
+	}
+}
+
+func TestWorldMousePosition(test *testing.T) {
+	cases := []struct {
+		name           string
+		mouseX, mouseY int
+		worldX, worldY int
+		width, height  int
+	}{
+		{"upper left", 2, 2, 3, 2, 20, 10},
+		{"lower right", 7, 5, 8, 5, 20, 10},
+		{"outside of viewport", 1, 1, -1, -1, 20, 10},
+		{"outside of source", 7, 2, -1, -1, 4, 2},
+		{"inside of small source", 5, 3, 3, 1, 4, 2},
+	}
+
+	for _, c := range cases {
+		test.Run(c.name, func(test *testing.T) {
+			con, _, view := newTestViewport(test, c.width, c.height)
+
+			assert.NoError(test, view.SetCamera(3, 2))
+			con.propagateMousePosition(c.mouseX, c.mouseY, true)
+
+			x, y := view.WorldMousePosition()
+			assert.Equal(test, c.worldX, x)
+			assert.Equal(test, c.worldY, y)
+		})
+	}
+
+	// Consoles that are no viewport don't have a world position.
+	con, _, _ := newTestViewport(test, 20, 10)
+	con.propagateMousePosition(3, 3, true)
+	x, y := con.WorldMousePosition()
+	assert.Equal(test, -1, x)
+	assert.Equal(test, -1, y)
+}
+
+func TestViewportSourceResize(test *testing.T) {
+	con, source, view := newTestViewport(test, 20, 10)
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	// The size of the source is read under its lock, which is meant to be run
+	// with the race detector.
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+				_ = source.Resize(10+i%10, 5+i%5)
+			}
+		}
+	}()
+
+	for i := 0; i < 200; i++ {
+		assert.NoError(test, view.CenterOn(i%20, i%10))
+		con.propagateMousePosition(i%10, i%10, true)
+		_ = con.RenderImage()
+	}
+
+	close(done)
+	wg.Wait()
+}
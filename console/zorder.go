@@ -0,0 +1,200 @@
+package console
+
+import "fmt"
+
+// SetZIndex changes the z-index of the component. Components with a higher z-index
+// are drawn on top of the ones with a lower z-index and receive the mouse events
+// first. Components with the same z-index are ordered by the time they were added.
+// Like AddComponent this is safe to use from component callbacks.
+func (c *Console) SetZIndex(component Component, z int) error {
+	return c.reorderComponent(component, func() (int, bool) {
+		return z, true
+	})
+}
+
+// ZIndex returns the z-index of the component.
+func (c *Console) ZIndex(component Component) (int, error) {
+	c.compMtx.Lock()
+	defer c.compMtx.Unlock()
+
+	if c.componentIndex(component.ID()) < 0 {
+		return 0, fmt.Errorf("component is not mounted to the console")
+	}
+	return c.zIndex[component.ID()], nil
+}
+
+// BringToFront moves the component on top of all other components. If the component
+// has a lower z-index than the topmost component it is raised to its z-index.
+// Like AddComponent this is safe to use from component callbacks.
+func (c *Console) BringToFront(component Component) error {
+	return c.reorderComponent(component, func() (int, bool) {
+		z := c.zIndex[component.ID()]
+		if top := c.zIndex[c.components[len(c.components)-1].ID()]; top > z {
+			z = top
+		}
+		return z, true
+	})
+}
+
+// SendToBack moves the component below all other components. If the component
+// has a higher z-index than the bottommost component it is lowered to its z-index.
+// Like AddComponent this is safe to use from component callbacks.
+func (c *Console) SendToBack(component Component) error {
+	return c.reorderComponent(component, func() (int, bool) {
+		z := c.zIndex[component.ID()]
+		if bottom := c.zIndex[c.components[0].ID()]; bottom < z {
+			z = bottom
+		}
+		return z, false
+	})
+}
+
+// Components returns the mounted components from the bottommost to the topmost.
+func (c *Console) Components() []Component {
+	return c.componentList()
+}
+
+// ComponentAt returns the topmost visible component that contains the given cell
+// or nil if there is none.
+func (c *Console) ComponentAt(x, y int) Component {
+	components := c.componentList()
+	for i := len(components) - 1; i >= 0; i-- {
+		cx, cy := components[i].Position()
+		w, h := components[i].Size()
+		if components[i].ShouldDraw() && x >= cx && y >= cy && x < cx+w && y < cy+h {
+			return components[i]
+		}
+	}
+	return nil
+}
+
+// componentList returns a copy of the mounted components in draw order. Mutations
+// that are queued until the end of the running passes are not included.
+func (c *Console) componentList() []Component {
+	c.compMtx.Lock()
+	defer c.compMtx.Unlock()
+
+	return append([]Component(nil), c.components...)
+}
+
+// beginComponentPass marks the start of a pass over the components of the console.
+// Until all passes are finished mutations of the components are queued, so they
+// can be used safely from callbacks of the components.
+func (c *Console) beginComponentPass() {
+	c.compMtx.Lock()
+	defer c.compMtx.Unlock()
+
+	c.compPasses++
+}
+
+// endComponentPass marks the end of a pass and applies the queued mutations if
+// it was the last running pass.
+func (c *Console) endComponentPass() {
+	c.compMtx.Lock()
+	defer c.compMtx.Unlock()
+
+	c.compPasses--
+	if c.compPasses > 0 {
+		return
+	}
+
+	for i := range c.pending {
+		c.pending[i]()
+	}
+	c.pending = nil
+	c.pendingMounted = map[string]Component{}
+}
+
+// mutateComponents applies the mutation right away or queues it if a pass over the
+// components is running. The component lock needs to be held.
+func (c *Console) mutateComponents(mutation func()) {
+	if c.compPasses == 0 {
+		mutation()
+		return
+	}
+
+	c.pending = append(c.pending, mutation)
+}
+
+// recordPending records which component will be mounted with the id once the queued
+// mutations are applied. nil records that it will be removed. The component lock
+// needs to be held.
+func (c *Console) recordPending(id string, mounted Component) {
+	if c.compPasses > 0 {
+		c.pendingMounted[id] = mounted
+	}
+}
+
+// mountedComponent returns the component that is mounted with the id, taking the
+// queued mutations into account, or nil if there is none. The component lock needs
+// to be held.
+func (c *Console) mountedComponent(id string) Component {
+	if mounted, ok := c.pendingMounted[id]; ok {
+		return mounted
+	}
+	if i := c.componentIndex(id); i >= 0 {
+		return c.components[i]
+	}
+	return nil
+}
+
+// reorderComponent moves the component to the z-index and position returned by place.
+// The component lock is held while place is called.
+func (c *Console) reorderComponent(component Component, place func() (z int, top bool)) error {
+	c.compMtx.Lock()
+	defer c.compMtx.Unlock()
+
+	if c.mountedComponent(component.ID()) == nil {
+		return fmt.Errorf("component is not mounted to the console")
+	}
+
+	c.mutateComponents(func() {
+		if c.componentIndex(component.ID()) < 0 {
+			return
+		}
+
+		z, top := place()
+		c.removeComponent(component.ID())
+		c.insertComponent(component, z, top)
+	})
+	return nil
+}
+
+// componentIndex returns the position of the component with the id in the draw
+// order or -1 if it isn't mounted. The component lock needs to be held.
+func (c *Console) componentIndex(id string) int {
+	for i := range c.components {
+		if c.components[i].ID() == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// insertComponent inserts the component with the z-index into the draw order. If
+// top is true it is placed on top of the components with the same z-index, otherwise
+// below them. The component lock needs to be held.
+func (c *Console) insertComponent(component Component, z int, top bool) {
+	pos := len(c.components)
+	for i := range c.components {
+		other := c.zIndex[c.components[i].ID()]
+		if other > z || (!top && other == z) {
+			pos = i
+			break
+		}
+	}
+
+	c.components = append(c.components, nil)
+	copy(c.components[pos+1:], c.components[pos:])
+	c.components[pos] = component
+	c.zIndex[component.ID()] = z
+}
+
+// removeComponent removes the component with the id from the draw order. The
+// component lock needs to be held.
+func (c *Console) removeComponent(id string) {
+	if i := c.componentIndex(id); i >= 0 {
+		c.components = append(c.components[:i], c.components[i+1:]...)
+		delete(c.zIndex, id)
+	}
+}
@@ -0,0 +1,210 @@
+package console
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/BigJk/ramen/font"
+	"github.com/stretchr/testify/assert"
+)
+
+type testComponent struct {
+	*ComponentBase
+	onUpdate func(con *Console)
+}
+
+func (tc *testComponent) Update(con *Console, timeElapsed float64) bool {
+	if tc.onUpdate != nil {
+		tc.onUpdate(con)
+	}
+	return true
+}
+
+func (tc *testComponent) Draw(con *Console, timeElapsed float64) {}
+func (tc *testComponent) FocusOnClick() bool                     { return false }
+
+func TestComponentOrder(t *testing.T) {
+	con, err := New(10, 10, font.DefaultFont, "")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	a := &testComponent{ComponentBase: NewComponentBase(0, 0, 5, 5)}
+	b := &testComponent{ComponentBase: NewComponentBase(2, 2, 5, 5)}
+	c := &testComponent{ComponentBase: NewComponentBase(4, 4, 5, 5)}
+
+	con.AddComponent(a)
+	con.AddComponent(b)
+	con.AddComponent(c)
+	assert.Equal(t, []Component{a, b, c}, con.Components())
+	assert.Equal(t, c, con.ComponentAt(4, 4))
+	assert.Equal(t, a, con.ComponentAt(1, 1))
+	assert.Nil(t, con.ComponentAt(9, 0))
+
+	assert.NoError(t, con.BringToFront(a))
+	assert.Equal(t, []Component{b, c, a}, con.Components())
+	assert.Equal(t, a, con.ComponentAt(4, 4))
+
+	assert.NoError(t, con.SendToBack(c))
+	assert.Equal(t, []Component{c, b, a}, con.Components())
+
+	assert.NoError(t, con.SetZIndex(c, 10))
+	assert.Equal(t, []Component{b, a, c}, con.Components())
+
+	assert.NoError(t, con.BringToFront(b))
+	assert.Equal(t, []Component{a, c, b}, con.Components())
+	z, err := con.ZIndex(b)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, z)
+
+	b.Show(false)
+	assert.Equal(t, c, con.ComponentAt(4, 4))
+
+	con.RemoveComponent(b)
+	assert.False(t, con.HasComponent(b))
+	assert.Error(t, con.BringToFront(b))
+	assert.Equal(t, []Component{a, c}, con.Components())
+}
+
+func TestComponentMutationInCallback(t *testing.T) {
+	con, err := New(10, 10, font.DefaultFont, "")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	dialog := &testComponent{ComponentBase: NewComponentBase(2, 2, 5, 5)}
+	opener := &testComponent{ComponentBase: NewComponentBase(0, 0, 2, 1)}
+	opener.onUpdate = func(con *Console) {
+		con.AddComponent(dialog)
+		con.RemoveComponent(opener)
+		assert.NoError(t, con.BringToFront(dialog))
+		assert.NoError(t, con.Focus(dialog))
+
+		// The mutations are applied after the update pass.
+		assert.True(t, con.HasComponent(dialog))
+		assert.False(t, con.HasComponent(opener))
+		assert.Equal(t, []Component{opener}, con.Components())
+	}
+
+	con.AddComponent(opener)
+	con.propagateComponentUpdates(0)
+
+	assert.Equal(t, []Component{dialog}, con.Components())
+	assert.Equal(t, dialog, con.Focused())
+	assert.True(t, dialog.IsFocused())
+}
+
+func TestConcurrentComponentRemoval(t *testing.T) {
+	con, err := New(10, 10, font.DefaultFont, "")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	sub, err := con.CreateSubConsole(0, 0, 5, 5)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	// Draw and add components from other goroutines while closed components are
+	// removed by the update, which is meant to be run with the race detector.
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				_ = con.RenderImage()
+			}
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+				comp := &testComponent{ComponentBase: NewComponentBase(i%5, 0, 1, 1)}
+				comp.Close()
+				sub.AddComponent(comp)
+				con.PushEvent(Event{Type: EventMousePress, MouseX: i % 5})
+			}
+		}
+	}()
+
+	for i := 0; i < 200; i++ {
+		comp := &testComponent{ComponentBase: NewComponentBase(i%10, 1, 1, 1)}
+		comp.onUpdate = func(con *Console) {
+			comp.Close()
+		}
+		con.AddComponent(comp)
+
+		con.propagateComponentUpdates(0)
+		assert.NoError(t, con.dispatchEvents())
+	}
+
+	close(done)
+	wg.Wait()
+
+	con.propagateComponentUpdates(0)
+	assert.Empty(t, con.Components())
+	assert.Empty(t, sub.Components())
+}
+
+func TestConcurrentSubConsolePriority(t *testing.T) {
+	con, err := New(10, 10, font.DefaultFont, "")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var subs []*Console
+	for i := 0; i < 3; i++ {
+		sub, err := con.CreateSubConsole(i, 0, 2, 2)
+		if !assert.NoError(t, err) {
+			return
+		}
+		subs = append(subs, sub)
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	// Priorities are changed while the sub-consoles are sorted and drawn, which
+	// is meant to be run with the race detector.
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+				assert.NoError(t, subs[i%3].SetPriority(i%5))
+			}
+		}
+	}()
+
+	for i := 0; i < 200; i++ {
+		_ = con.RenderImage()
+		if i%20 == 0 {
+			sub, err := con.CreateSubConsole(0, 0, 1, 1)
+			if assert.NoError(t, err) {
+				assert.NoError(t, con.RemoveSubConsole(sub))
+			}
+		}
+	}
+
+	close(done)
+	wg.Wait()
+
+	assert.NoError(t, subs[0].SetPriority(2))
+	assert.NoError(t, subs[1].SetPriority(0))
+	assert.NoError(t, subs[2].SetPriority(1))
+	assert.Equal(t, []*Console{subs[1], subs[2], subs[0]}, con.SubConsoles)
+}
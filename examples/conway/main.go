@@ -42,16 +42,18 @@ func main() {
 	}
 
 	con.SetTickHook(func(timeElapsed float64) error {
-		if ebiten.IsKeyPressed(ebiten.KeySpace) {
+		input := con.Input()
+
+		if input.IsKeyPressed(ebiten.KeySpace) {
 			step()
 		}
 
-		if ebiten.IsKeyPressed(ebiten.KeyK) {
+		if input.IsKeyPressed(ebiten.KeyK) {
 			board = createBoard(width, height)
 		}
 
-		if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
-			cx, cy := ebiten.CursorPosition()
+		if input.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+			cx, cy := input.CursorPosition()
 
 			bx := cx / font.DefaultFont.TileWidth
 			by := cy / font.DefaultFont.TileHeight
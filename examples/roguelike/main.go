@@ -7,7 +7,6 @@ import (
 	"github.com/BigJk/ramen/font"
 	"github.com/BigJk/ramen/t"
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"strings"
 )
 
@@ -73,19 +72,21 @@ func main() {
 	*/
 
 	rootView.SetTickHook(func(timeElapsed float64) error {
-		if inpututil.IsKeyJustPressed(ebiten.KeyW) && !isSolid(player.X, player.Y-1) {
+		input := rootView.Input()
+
+		if input.IsKeyJustPressed(ebiten.KeyW) && !isSolid(player.X, player.Y-1) {
 			player.Y -= 1
 		}
 
-		if inpututil.IsKeyJustPressed(ebiten.KeyS) && !isSolid(player.X, player.Y+1) {
+		if input.IsKeyJustPressed(ebiten.KeyS) && !isSolid(player.X, player.Y+1) {
 			player.Y += 1
 		}
 
-		if inpututil.IsKeyJustPressed(ebiten.KeyA) && !isSolid(player.X-1, player.Y) {
+		if input.IsKeyJustPressed(ebiten.KeyA) && !isSolid(player.X-1, player.Y) {
 			player.X -= 1
 		}
 
-		if inpututil.IsKeyJustPressed(ebiten.KeyD) && !isSolid(player.X+1, player.Y) {
+		if input.IsKeyJustPressed(ebiten.KeyD) && !isSolid(player.X+1, player.Y) {
 			player.X += 1
 		}
 
@@ -0,0 +1,117 @@
+package font
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Charmap maps unicode runes to the chars (glyph indices) of a font.
+type Charmap map[rune]int
+
+// NewCharmap creates a charmap from a table where the rune at position i
+// is mapped to the char i.
+func NewCharmap(table []rune) Charmap {
+	cm := make(Charmap, len(table))
+	for i := range table {
+		if _, ok := cm[table[i]]; !ok {
+			cm[table[i]] = i
+		}
+	}
+	return cm
+}
+
+// LoadCharmap loads a custom charmap from a reader. Each line contains a rune and
+// the char it should be mapped to, separated by whitespace. The rune can either be
+// written as the character itself or as code point like U+2554 or 0x2554. The char
+// can be written as decimal or hexadecimal (0xC9) number. Empty lines and lines
+// starting with '#' are ignored.
+//
+//	# box drawing
+//	╔ 201
+//	U+2550 0xCD
+func LoadCharmap(reader io.Reader) (Charmap, error) {
+	cm := Charmap{}
+
+	scanner := bufio.NewScanner(reader)
+	line := 0
+	for scanner.Scan() {
+		line++
+
+		text := strings.TrimSpace(scanner.Text())
+		if len(text) == 0 || text[0] == '#' {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected rune and char", line)
+		}
+
+		r, err := parseCharmapRune(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		char, err := strconv.ParseInt(fields[1], 0, 32)
+		if err != nil || char < 0 {
+			return nil, fmt.Errorf("line %d: invalid char '%s'", line, fields[1])
+		}
+
+		cm[r] = int(char)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return cm, nil
+}
+
+// Map returns the char for the given rune. If the rune is not in the charmap
+// false is returned.
+func (cm Charmap) Map(r rune) (int, bool) {
+	char, ok := cm[r]
+	return char, ok
+}
+
+func parseCharmapRune(text string) (rune, error) {
+	lower := strings.ToLower(text)
+	if strings.HasPrefix(lower, "u+") || strings.HasPrefix(lower, "0x") {
+		val, err := strconv.ParseUint(text[2:], 16, 32)
+		if err != nil {
+			return 0, fmt.Errorf("invalid code point '%s'", text)
+		}
+		return rune(val), nil
+	}
+
+	r, size := utf8.DecodeRuneInString(text)
+	if r == utf8.RuneError || size != len(text) {
+		return 0, fmt.Errorf("invalid rune '%s'", text)
+	}
+	return r, nil
+}
+
+// CP437 is the charmap for fonts that are laid out like the IBM Code Page 437,
+// which is the layout of most roguelike tilesets.
+var CP437 = NewCharmap([]rune(
+	"\x00☺☻♥♦♣♠•◘○◙♂♀♪♫☼" +
+		"►◄↕‼¶§▬↨↑↓→←∟↔▲▼" +
+		" !\"#$%&'()*+,-./" +
+		"0123456789:;<=>?" +
+		"@ABCDEFGHIJKLMNO" +
+		"PQRSTUVWXYZ[\\]^_" +
+		"`abcdefghijklmno" +
+		"pqrstuvwxyz{|}~⌂" +
+		"ÇüéâäàåçêëèïîìÄÅ" +
+		"ÉæÆôöòûùÿÖÜ¢£¥₧ƒ" +
+		"áíóúñÑªº¿⌐¬½¼¡«»" +
+		"░▒▓│┤╡╢╖╕╣║╗╝╜╛┐" +
+		"└┴┬├─┼╞╟╚╔╩╦╠═╬╧" +
+		"╨╤╥╙╘╒╓╫╪┘┌█▄▌▐▀" +
+		"αßΓπΣσµτΦΘΩδ∞φε∩" +
+		"≡±≥≤⌠⌡÷≈°∙·√ⁿ²■\u00a0",
+))
@@ -0,0 +1,61 @@
+package font
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCP437RoundTrip(t *testing.T) {
+	assert.Len(t, CP437, 256)
+
+	runes := make([]rune, 256)
+	seen := make([]bool, 256)
+	for r, char := range CP437 {
+		if !assert.True(t, char >= 0 && char < 256, "char of %q out of range", r) {
+			continue
+		}
+		assert.False(t, seen[char], "char %d mapped twice", char)
+		seen[char] = true
+		runes[char] = r
+	}
+
+	for char := range runes {
+		mapped, ok := CP437.Map(runes[char])
+		assert.True(t, ok)
+		assert.Equal(t, char, mapped)
+	}
+
+	for r, char := range map[rune]int{0: 0, '☺': 1, ' ': 32, 'A': 65, '~': 126, '⌂': 127, 'Ç': 128, '░': 176, '╔': 201, '█': 219, 'α': 224, '■': 254, ' ': 255} {
+		assert.Equal(t, r, runes[char])
+	}
+}
+
+func TestMapRune(t *testing.T) {
+	f := &Font{Charmap: CP437}
+	assert.Equal(t, 201, f.MapRune('╔'))
+	assert.Equal(t, 65, f.MapRune('A'))
+
+	_, ok := CP437.Map('€')
+	assert.False(t, ok)
+	assert.Equal(t, '?', rune(f.MapRune('€')))
+
+	// Without a charmap the rune value is the char.
+	assert.Equal(t, 0x2554, (&Font{}).MapRune('╔'))
+
+	// Without '?' in the charmap the rune value is used.
+	assert.Equal(t, int('€'), (&Font{Charmap: Charmap{'A': 1}}).MapRune('€'))
+}
+
+func TestLoadCharmap(t *testing.T) {
+	cm, err := LoadCharmap(strings.NewReader("# box drawing\n╔ 201\n\nU+2550 0xCD\n0x41 65\n"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, Charmap{'╔': 201, '═': 0xCD, 'A': 65}, cm)
+	}
+
+	for _, text := range []string{"╔", "╔ x", "╔ -1", "U+zz 1", "ab 1"} {
+		_, err := LoadCharmap(strings.NewReader(text))
+		assert.Error(t, err, text)
+	}
+}
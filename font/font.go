@@ -18,11 +18,13 @@ import (
 type Font struct {
 	File       string
 	Image      *ebiten.Image
+	Source     image.Image
 	TileWidth  int
 	TileHeight int
 	TileSizeX  int
 	TileSizeY  int
 	Tiles      map[int]bool
+	Charmap    Charmap
 }
 
 // New creates a new font.
@@ -49,21 +51,36 @@ func NewFromReader(reader io.Reader, tileWidth, tileHeight int) (*Font, error) {
 		return nil, err
 	}
 
-	return &Font{"", ebiten.NewImageFromImage(img), tileWidth, tileHeight, img.Bounds().Max.X / tileWidth, img.Bounds().Max.Y / tileHeight, make(map[int]bool)}, nil
+	return &Font{"", ebiten.NewImageFromImage(img), img, tileWidth, tileHeight, img.Bounds().Max.X / tileWidth, img.Bounds().Max.Y / tileHeight, make(map[int]bool), nil}, nil
 }
 
 // ToSubImage extracts the image of a given char from the base image of the font.
 func (f *Font) ToSubImage(char int) *ebiten.Image {
-	x := (int(char) % f.TileSizeX) * f.TileWidth
-	y := (int(char) / f.TileSizeX) * f.TileHeight
+	r, ok := f.ToSubRect(char)
+	if !ok {
+		return nil
+	}
+
+	return f.Image.SubImage(r).(*ebiten.Image)
+}
+
+// ToSubRect returns the area of a given char inside the base image of the font.
+// If the char is not contained in the font false is returned.
+func (f *Font) ToSubRect(char int) (image.Rectangle, bool) {
+	if char < 0 {
+		return image.Rectangle{}, false
+	}
+
+	x := (char % f.TileSizeX) * f.TileWidth
+	y := (char / f.TileSizeX) * f.TileHeight
 
 	r := image.Rect(x, y, x+f.TileWidth, y+f.TileHeight)
 
-	if r.Max.X > f.Image.Bounds().Max.X || r.Max.Y > f.Image.Bounds().Max.Y {
-		return nil
+	if r.Max.X > f.Source.Bounds().Max.X || r.Max.Y > f.Source.Bounds().Max.Y {
+		return image.Rectangle{}, false
 	}
 
-	return f.Image.SubImage(r).(*ebiten.Image)
+	return r, true
 }
 
 // SetTiles changes if a char is a colored tile or not.
@@ -80,3 +97,22 @@ func (f *Font) IsTile(char int) bool {
 	val, ok := f.Tiles[char]
 	return ok && val
 }
+
+// MapRune returns the char that represents the rune in the font. If the font has no
+// charmap the rune value is used as char. Runes that are missing in the charmap are
+// mapped to '?'.
+func (f *Font) MapRune(r rune) int {
+	if f.Charmap == nil {
+		return int(r)
+	}
+
+	if char, ok := f.Charmap.Map(r); ok {
+		return char
+	}
+
+	if char, ok := f.Charmap.Map('?'); ok {
+		return char
+	}
+
+	return int(r)
+}
@@ -10,9 +10,10 @@ type BackgroundTransform struct {
 	color concolor.Color
 }
 
-// Transform sets the background color of a cell
+// Transform sets the background color of a cell and removes its palette reference
 func (b BackgroundTransform) Transform(cell *ramen.Cell) error {
 	cell.Background = b.color
+	cell.BackgroundIndex = 0
 	return nil
 }
 
@@ -0,0 +1,69 @@
+package t
+
+import (
+	"github.com/BigJk/ramen"
+	"github.com/BigJk/ramen/concolor"
+)
+
+// BlendTransform composites a color onto the foreground or background color of a cell
+type BlendTransform struct {
+	color      concolor.Color
+	mode       concolor.BlendMode
+	foreground bool
+}
+
+// Transform composites the color onto the color of the cell and removes its palette reference
+func (b BlendTransform) Transform(cell *ramen.Cell) error {
+	if b.foreground {
+		cell.Foreground = cell.Foreground.Blend(b.color, b.mode)
+		cell.ForegroundIndex = 0
+	} else {
+		cell.Background = cell.Background.Blend(b.color, b.mode)
+		cell.BackgroundIndex = 0
+	}
+	return nil
+}
+
+// BlendBackground creates a new transformer that composites the given color onto the
+// background of a cell with the blend mode. The alpha of the color is used as opacity.
+func BlendBackground(newBackground concolor.Color, mode concolor.BlendMode) BlendTransform {
+	return BlendTransform{newBackground, mode, false}
+}
+
+// BlendForeground creates a new transformer that composites the given color onto the
+// foreground of a cell with the blend mode. The alpha of the color is used as opacity.
+func BlendForeground(newForeground concolor.Color, mode concolor.BlendMode) BlendTransform {
+	return BlendTransform{newForeground, mode, true}
+}
+
+// LerpTransform linearly interpolates the foreground or background color of a cell
+// towards a color
+type LerpTransform struct {
+	color      concolor.Color
+	amount     float64
+	foreground bool
+}
+
+// Transform interpolates the color of the cell towards the color and removes its palette reference
+func (l LerpTransform) Transform(cell *ramen.Cell) error {
+	if l.foreground {
+		cell.Foreground = cell.Foreground.Lerp(l.color, l.amount)
+		cell.ForegroundIndex = 0
+	} else {
+		cell.Background = cell.Background.Lerp(l.color, l.amount)
+		cell.BackgroundIndex = 0
+	}
+	return nil
+}
+
+// LerpBackground creates a new transformer that interpolates the background of a cell
+// towards the given color. An amount of 0 keeps the background and 1 replaces it.
+func LerpBackground(newBackground concolor.Color, amount float64) LerpTransform {
+	return LerpTransform{newBackground, amount, false}
+}
+
+// LerpForeground creates a new transformer that interpolates the foreground of a cell
+// towards the given color. An amount of 0 keeps the foreground and 1 replaces it.
+func LerpForeground(newForeground concolor.Color, amount float64) LerpTransform {
+	return LerpTransform{newForeground, amount, true}
+}
@@ -10,9 +10,10 @@ type ForegroundTransform struct {
 	color concolor.Color
 }
 
-// Transform sets the foreground color of a cell
+// Transform sets the foreground color of a cell and removes its palette reference
 func (f ForegroundTransform) Transform(cell *ramen.Cell) error {
 	cell.Foreground = f.color
+	cell.ForegroundIndex = 0
 	return nil
 }
 
@@ -0,0 +1,134 @@
+package t
+
+import (
+	"math"
+
+	"github.com/BigJk/ramen"
+	"github.com/BigJk/ramen/concolor"
+)
+
+// GradientTransform sets the foreground or background color of a cell to a
+// color between from and to, depending on the position of the cell in the area.
+type GradientTransform struct {
+	from       concolor.Color
+	to         concolor.Color
+	foreground bool
+	amount     func(x, y int, area Area) float64
+}
+
+// Transform sets the color of a cell to the start color of the gradient
+func (g GradientTransform) Transform(cell *ramen.Cell) error {
+	return g.TransformPosition(cell, 0, 0, Area{Width: 1, Height: 1})
+}
+
+// TransformPosition sets the color of a cell depending on its position in the area and
+// removes its palette reference
+func (g GradientTransform) TransformPosition(cell *ramen.Cell, x, y int, area Area) error {
+	col := g.from.Lerp(g.to, g.amount(x, y, area))
+	if g.foreground {
+		cell.Foreground = col
+		cell.ForegroundIndex = 0
+	} else {
+		cell.Background = col
+		cell.BackgroundIndex = 0
+	}
+	return nil
+}
+
+// BackgroundGradient creates a transformer that sets the background to a linear gradient
+// over the transformed area. The angle is given in degree, 0 runs from left to right and
+// 90 from top to bottom.
+func BackgroundGradient(from, to concolor.Color, angle float64) GradientTransform {
+	return GradientTransform{from, to, false, linearAmount(angle)}
+}
+
+// ForegroundGradient creates a transformer that sets the foreground to a linear gradient
+// over the transformed area. The angle is given in degree, 0 runs from left to right and
+// 90 from top to bottom.
+func ForegroundGradient(from, to concolor.Color, angle float64) GradientTransform {
+	return GradientTransform{from, to, true, linearAmount(angle)}
+}
+
+// BackgroundRadialGradient creates a transformer that sets the background to a radial
+// gradient from the center of the transformed area to its corners.
+func BackgroundRadialGradient(inner, outer concolor.Color) GradientTransform {
+	return GradientTransform{inner, outer, false, radialAmount}
+}
+
+// ForegroundRadialGradient creates a transformer that sets the foreground to a radial
+// gradient from the center of the transformed area to its corners.
+func ForegroundRadialGradient(inner, outer concolor.Color) GradientTransform {
+	return GradientTransform{inner, outer, true, radialAmount}
+}
+
+// BackgroundNoise creates a transformer that sets the background to a random color
+// between from and to. The noise is deterministic for the same seed and position.
+func BackgroundNoise(from, to concolor.Color, seed int64) GradientTransform {
+	return GradientTransform{from, to, false, func(x, y int, area Area) float64 {
+		return noise(x, y, seed)
+	}}
+}
+
+// BackgroundDither creates a transformer that dithers the background between the colors
+// a and b. ratio (0 - 1) is the share of cells that get the color b. The pattern is
+// deterministic for the same seed and position.
+func BackgroundDither(a, b concolor.Color, ratio float64, seed int64) GradientTransform {
+	return GradientTransform{a, b, false, func(x, y int, area Area) float64 {
+		if noise(x, y, seed) < ratio {
+			return 1
+		}
+		return 0
+	}}
+}
+
+// BackgroundChecker creates a transformer that sets the background to a checkerboard
+// pattern of the colors a and b.
+func BackgroundChecker(a, b concolor.Color) GradientTransform {
+	return GradientTransform{a, b, false, func(x, y int, area Area) float64 {
+		return float64((x + y) & 1)
+	}}
+}
+
+func linearAmount(angle float64) func(x, y int, area Area) float64 {
+	dx, dy := math.Cos(angle*math.Pi/180), math.Sin(angle*math.Pi/180)
+
+	return func(x, y int, area Area) float64 {
+		project := func(x, y int) float64 {
+			return float64(x-area.X)*dx + float64(y-area.Y)*dy
+		}
+
+		min, max := math.Inf(1), math.Inf(-1)
+		for _, corner := range [][2]int{{area.X, area.Y}, {area.X + area.Width - 1, area.Y}, {area.X, area.Y + area.Height - 1}, {area.X + area.Width - 1, area.Y + area.Height - 1}} {
+			p := project(corner[0], corner[1])
+			min = math.Min(min, p)
+			max = math.Max(max, p)
+		}
+
+		if max-min < 1e-9 {
+			return 0
+		}
+		return (project(x, y) - min) / (max - min)
+	}
+}
+
+func radialAmount(x, y int, area Area) float64 {
+	cx := float64(area.X) + float64(area.Width-1)/2
+	cy := float64(area.Y) + float64(area.Height-1)/2
+
+	maxDist := math.Hypot(float64(area.Width-1)/2, float64(area.Height-1)/2)
+	if maxDist < 1e-9 {
+		return 0
+	}
+	return math.Min(math.Hypot(float64(x)-cx, float64(y)-cy)/maxDist, 1)
+}
+
+// noise returns a pseudo random value between 0 and 1 for the position.
+func noise(x, y int, seed int64) float64 {
+	h := uint64(seed) ^ uint64(int64(x))*0x9E3779B97F4A7C15 ^ uint64(int64(y))*0xC2B2AE3D27D4EB4F
+	h ^= h >> 33
+	h *= 0xFF51AFD7ED558CCD
+	h ^= h >> 33
+	h *= 0xC4CEB9FE1A85EC53
+	h ^= h >> 33
+	return float64(h>>11) / (1 << 53)
+}
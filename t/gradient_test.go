@@ -0,0 +1,48 @@
+package t
+
+import (
+	"testing"
+
+	"github.com/BigJk/ramen"
+	"github.com/BigJk/ramen/concolor"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackgroundGradient(t *testing.T) {
+	area := Area{X: 2, Y: 3, Width: 5, Height: 2}
+	gradient := BackgroundGradient(concolor.Black, concolor.White, 0)
+
+	var cell ramen.Cell
+	if assert.NoError(t, Apply(gradient, &cell, 2, 3, area)) {
+		assert.Equal(t, concolor.Black, cell.Background)
+	}
+
+	if assert.NoError(t, Apply(gradient, &cell, 6, 4, area)) {
+		assert.Equal(t, concolor.White, cell.Background)
+	}
+
+	if assert.NoError(t, Apply(gradient, &cell, 4, 3, area)) {
+		assert.Equal(t, concolor.RGB(128, 128, 128), cell.Background)
+	}
+}
+
+func TestBackgroundRadialGradient(t *testing.T) {
+	area := Area{Width: 5, Height: 5}
+	gradient := BackgroundRadialGradient(concolor.Red, concolor.Blue)
+
+	var cell ramen.Cell
+	if assert.NoError(t, Apply(gradient, &cell, 2, 2, area)) {
+		assert.Equal(t, concolor.Red, cell.Background)
+	}
+
+	if assert.NoError(t, Apply(gradient, &cell, 0, 0, area)) {
+		assert.Equal(t, concolor.Blue, cell.Background)
+	}
+}
+
+func TestApplyPlainTransformer(t *testing.T) {
+	var cell ramen.Cell
+	if assert.NoError(t, Apply(Char('@'), &cell, 10, 10, Area{})) {
+		assert.Equal(t, int('@'), cell.Char)
+	}
+}
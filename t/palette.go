@@ -0,0 +1,34 @@
+package t
+
+import (
+	"github.com/BigJk/ramen"
+	"github.com/BigJk/ramen/concolor"
+)
+
+// PaletteTransform sets palette references of a cell
+type PaletteTransform struct {
+	index      concolor.PaletteIndex
+	background bool
+}
+
+// Transform sets the palette reference of a cell
+func (p PaletteTransform) Transform(cell *ramen.Cell) error {
+	if p.background {
+		cell.BackgroundIndex = p.index
+	} else {
+		cell.ForegroundIndex = p.index
+	}
+	return nil
+}
+
+// ForegroundIndex creates a new transformer that sets the foreground of a cell to the
+// color at the given index of the palette of the console.
+func ForegroundIndex(index int) PaletteTransform {
+	return PaletteTransform{index: concolor.Index(index)}
+}
+
+// BackgroundIndex creates a new transformer that sets the background of a cell to the
+// color at the given index of the palette of the console.
+func BackgroundIndex(index int) PaletteTransform {
+	return PaletteTransform{index: concolor.Index(index), background: true}
+}
@@ -0,0 +1,43 @@
+package t
+
+import (
+	"testing"
+
+	"github.com/BigJk/ramen"
+	"github.com/BigJk/ramen/concolor"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColorTransformersClearPaletteIndex(t *testing.T) {
+	cases := []struct {
+		name        string
+		transformer Transformer
+		foreground  bool
+	}{
+		{"Foreground", Foreground(concolor.White), true},
+		{"Background", Background(concolor.White), false},
+		{"BlendForeground", BlendForeground(concolor.White, concolor.BlendMultiply), true},
+		{"BlendBackground", BlendBackground(concolor.White, concolor.BlendMultiply), false},
+		{"LerpForeground", LerpForeground(concolor.White, 0.5), true},
+		{"LerpBackground", LerpBackground(concolor.White, 0.5), false},
+		{"ForegroundGradient", ForegroundGradient(concolor.Black, concolor.White, 0), true},
+		{"BackgroundGradient", BackgroundGradient(concolor.Black, concolor.White, 0), false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cell := ramen.Cell{ForegroundIndex: concolor.Index(1), BackgroundIndex: concolor.Index(2)}
+			if !assert.NoError(t, Apply(c.transformer, &cell, 0, 0, Area{Width: 1, Height: 1})) {
+				return
+			}
+
+			if c.foreground {
+				assert.Equal(t, concolor.PaletteIndex(0), cell.ForegroundIndex)
+				assert.Equal(t, concolor.Index(2), cell.BackgroundIndex)
+			} else {
+				assert.Equal(t, concolor.Index(1), cell.ForegroundIndex)
+				assert.Equal(t, concolor.PaletteIndex(0), cell.BackgroundIndex)
+			}
+		})
+	}
+}
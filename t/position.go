@@ -0,0 +1,28 @@
+package t
+
+import "github.com/BigJk/ramen"
+
+// Area represents the area of cells a transformer is applied to.
+type Area struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
+}
+
+// PositionTransformer is a transformer that also knows the position of the cell and
+// the area that is transformed. This makes effects like gradients possible. If a
+// console supports it TransformPosition will be used instead of Transform.
+type PositionTransformer interface {
+	Transformer
+	TransformPosition(cell *ramen.Cell, x, y int, area Area) error
+}
+
+// Apply applies the transformer to the cell. If the transformer is a PositionTransformer
+// the position and area are passed to it.
+func Apply(transformer Transformer, cell *ramen.Cell, x, y int, area Area) error {
+	if pt, ok := transformer.(PositionTransformer); ok {
+		return pt.TransformPosition(cell, x, y, area)
+	}
+	return transformer.Transform(cell)
+}